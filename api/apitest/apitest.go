@@ -0,0 +1,105 @@
+// Package apitest provides helpers to test the API packages against a local server.
+package apitest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// Server is a test server answering every request with an empty JSON object. It records
+// the methods of the requests, and the scopes they are authenticated for by its Auth.
+type Server struct {
+	*httptest.Server
+
+	mutex     sync.Mutex
+	methods   []string
+	requested []scopes.Scope
+}
+
+// NewServer starts a Server, closed at the end of the test
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := new(Server)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mutex.Lock()
+		s.methods = append(s.methods, r.Method)
+		s.mutex.Unlock()
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// scopeRecorder is an authenticator that records the scopes requested
+type scopeRecorder struct {
+	oauth.AuthData
+	server *Server
+}
+
+func (a scopeRecorder) SetAuthHeader(scope scopes.Scope, header http.Header) error {
+	a.server.mutex.Lock()
+	a.server.requested = append(a.server.requested, scope)
+	a.server.mutex.Unlock()
+	return nil
+}
+
+// Auth returns an authenticator for the server, recording the scopes requested
+func (s *Server) Auth() oauth.ForgeAuthenticator {
+	return scopeRecorder{AuthData: oauth.AuthData{Host: s.URL}, server: s}
+}
+
+// Client returns a client of the server, authenticated by Auth
+func (s *Server) Client() *api.Client { return api.NewClient(s.Auth()) }
+
+// Reset forgets the recorded methods and scopes
+func (s *Server) Reset() {
+	s.mutex.Lock()
+	s.methods, s.requested = nil, nil
+	s.mutex.Unlock()
+}
+
+// Methods returns the methods of the requests, in order
+func (s *Server) Methods() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.methods...)
+}
+
+// Requested returns the scopes the requests were authenticated for, in order
+func (s *Server) Requested() []scopes.Scope {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]scopes.Scope(nil), s.requested...)
+}
+
+// OperationCase is a call expected to make a single request of the operation
+type OperationCase struct {
+	Op   api.Operation
+	Call func() error
+}
+
+// TestOperationScopes runs the calls, made to the server, checking each made a single
+// request with the method of its operation, authenticated for the scope of the operation.
+func TestOperationScopes(t *testing.T, server *Server, cases []OperationCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.Op.Name, func(t *testing.T) {
+			server.Reset()
+			if err := tc.Call(); err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+			if methods := server.Methods(); len(methods) != 1 || methods[0] != tc.Op.Method {
+				t.Errorf("Expected one %v request, got %v", tc.Op.Method, methods)
+			}
+			if requested := server.Requested(); len(requested) != 1 || requested[0] != tc.Op.Scope {
+				t.Errorf("Expected scope %q to be requested, got %q", tc.Op.Scope, requested)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultBatchConcurrency is the number of tasks a Batch runs at once
+const DefaultBatchConcurrency = 8
+
+// DefaultBatchRetryPolicy retries the failed tasks of a Batch whose client has no RetryPolicy
+var DefaultBatchRetryPolicy = RetryPolicy{MaxAttempts: 3, Wait: time.Second}
+
+// Task is a call of a Batch, g.e. the upload of an object; its result and error are
+// collected under its key
+type Task[T any] struct {
+	Key string
+	Run func(ctx context.Context) (T, error)
+}
+
+// BatchProgress is reported by a Batch each time a task completes
+type BatchProgress struct {
+	// Key of the completed task, and its error if it failed
+	Key string
+	Err error
+	// Done is the number of completed tasks, Failed the number of them that failed
+	Done   int
+	Failed int
+	Total  int
+}
+
+// Batch runs tasks concurrently, retrying the failed ones. A fatal error, g.e. an
+// unauthorized one, stops the batch: the tasks not started yet are skipped. g.e.
+//
+//	tasks := make([]api.Task[dm.ObjectDetails], 0, len(files))
+//	for _, file := range files {
+//		file := file
+//		tasks = append(tasks, api.Task[dm.ObjectDetails]{Key: file, Run: func(ctx context.Context) (dm.ObjectDetails, error) {
+//			return upload(ctx, file)
+//		}})
+//	}
+//	result, err := api.RunBatch(ctx, &api.Batch{Client: client}, tasks...)
+type Batch struct {
+	// Client whose RetryPolicy is used, it may be nil
+	Client *Client
+	// Concurrency is the number of tasks run at once, if 0 DefaultBatchConcurrency is used
+	Concurrency int
+	// Retry controls how many times, and after how long, failed tasks are run again. If nil
+	// the RetryPolicy of the client is used, or DefaultBatchRetryPolicy if it has none.
+	// If its MaxAttempts is 0 the one of DefaultBatchRetryPolicy is used.
+	Retry *RetryPolicy
+	// Retryable returns true if a task that failed with the error can be run again,
+	// if nil IsRetryable is used. Tasks that are not safe to run again should not be retried.
+	Retryable func(err error) bool
+	// Fatal returns true if the error stops the batch, if nil IsFatal is used
+	Fatal func(err error) bool
+	// Progress, if set, is called each time a task completes; calls are not concurrent
+	Progress func(progress BatchProgress)
+}
+
+// BatchResult are the results and errors of the tasks of a batch, by key
+type BatchResult[T any] struct {
+	Results map[string]T
+	Errors  map[string]error
+	// Skipped are the keys of the tasks not run because the batch stopped, in order
+	Skipped []string
+}
+
+// IsRetryable returns true for the errors of requests that may succeed if made again:
+// system issues and network errors
+func IsRetryable(err error) bool {
+	switch ErrorClass(err) {
+	case ClassSystemIssue, ClassNetwork:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsFatal returns true for the errors all the requests of a batch would fail with:
+// unauthorized, expired token and API incompatibilities
+func IsFatal(err error) bool {
+	var incompatible ErrAPIIncompatible
+	if errors.As(err, &incompatible) {
+		return true
+	}
+	switch ErrorClass(err) {
+	case ClassUnauthorized, ClassTokenExpired:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *Batch) concurrency() int {
+	if b.Concurrency <= 0 {
+		return DefaultBatchConcurrency
+	}
+	return b.Concurrency
+}
+
+func (b *Batch) retryPolicy() RetryPolicy {
+	var policy RetryPolicy
+	switch {
+	case b.Retry != nil:
+		policy = *b.Retry
+	case b.Client != nil && b.Client.Retry != nil:
+		policy = *b.Client.Retry
+	default:
+		return DefaultBatchRetryPolicy
+	}
+	// failed tasks, unlike rate limited requests, are not retried without limit
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = DefaultBatchRetryPolicy.MaxAttempts
+	}
+	return policy
+}
+
+func (b *Batch) retryable(err error) bool {
+	if b.Retryable == nil {
+		return IsRetryable(err)
+	}
+	return b.Retryable(err)
+}
+
+func (b *Batch) fatal(err error) bool {
+	if b.Fatal == nil {
+		return IsFatal(err)
+	}
+	return b.Fatal(err)
+}
+
+// RunBatch runs the tasks of the batch, a nil batch uses the defaults. The result holds
+// the tasks that completed, even if the batch stopped; err is the fatal error that
+// stopped it, or the error of ctx if it was canceled.
+func RunBatch[T any](ctx context.Context, batch *Batch, tasks ...Task[T]) (result *BatchResult[T], err error) {
+	if batch == nil {
+		batch = new(Batch)
+	}
+	keys := make(map[string]bool, len(tasks))
+	for _, task := range tasks {
+		if keys[task.Key] {
+			return nil, fmt.Errorf("batch: duplicate task key %q", task.Key)
+		}
+		keys[task.Key] = true
+	}
+	result = &BatchResult[T]{
+		Results: make(map[string]T, len(tasks)),
+		Errors:  make(map[string]error),
+	}
+	policy := batch.retryPolicy()
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mutex    sync.Mutex
+		progress = BatchProgress{Total: len(tasks)}
+		fatalErr error
+		started  = make([]bool, len(tasks))
+		indexes  = make(chan int)
+		wg       sync.WaitGroup
+	)
+	complete := func(key string, value T, err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		progress.Done++
+		if err != nil {
+			progress.Failed++
+			result.Errors[key] = err
+		} else {
+			result.Results[key] = value
+		}
+		if err != nil && fatalErr == nil && batch.fatal(err) {
+			fatalErr = fmt.Errorf("batch stopped by %v: %w", key, err)
+			cancel()
+		}
+		if batch.Progress != nil {
+			progress.Key, progress.Err = key, err
+			batch.Progress(progress)
+		}
+	}
+	run := func(task Task[T]) {
+		for attempt := 1; ; attempt++ {
+			value, err := task.Run(runCtx)
+			if err == nil || runCtx.Err() != nil || batch.fatal(err) || !batch.retryable(err) {
+				complete(task.Key, value, err)
+				return
+			}
+			wait, ok := policy.retry(attempt, nil)
+			if !ok {
+				complete(task.Key, value, err)
+				return
+			}
+			if sleepErr := sleep(runCtx, wait); sleepErr != nil {
+				complete(task.Key, value, err)
+				return
+			}
+		}
+	}
+
+	workers := batch.concurrency()
+	if workers > len(tasks) {
+		workers = len(tasks)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if runCtx.Err() != nil {
+					continue
+				}
+				started[i] = true
+				run(tasks[i])
+			}
+		}()
+	}
+FEED:
+	for i := range tasks {
+		select {
+		case <-runCtx.Done():
+			break FEED
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, task := range tasks {
+		if !started[i] {
+			result.Skipped = append(result.Skipped, task.Key)
+		}
+	}
+	if fatalErr != nil {
+		return result, fatalErr
+	}
+	return result, ctx.Err()
+}
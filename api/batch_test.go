@@ -0,0 +1,142 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+func TestRunBatch(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		attempts = make(map[string]int)
+		running  int32
+		maxRun   int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			max := atomic.LoadInt32(&maxRun)
+			if n <= max || atomic.CompareAndSwapInt32(&maxRun, max, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		key := strings.TrimPrefix(r.URL.Path, "/items/")
+		mutex.Lock()
+		attempts[key]++
+		attempt := attempts[key]
+		mutex.Unlock()
+		switch {
+		case key == "flaky" && attempt == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case key == "down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case key == "missing":
+			w.WriteHeader(http.StatusNotFound)
+		case key == "unauthorized":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.Write([]byte(`{"id":"` + key + `"}`))
+		}
+	}))
+	defer server.Close()
+
+	auth := static.New("token")
+	auth.Host = server.URL
+	client := api.NewClient(auth)
+	client.Retry = &api.RetryPolicy{MaxAttempts: 3, Wait: time.Millisecond}
+
+	newTasks := func(keys ...string) []api.Task[string] {
+		tasks := make([]api.Task[string], 0, len(keys))
+		for _, key := range keys {
+			key := key
+			tasks = append(tasks, api.Task[string]{Key: key, Run: func(ctx context.Context) (string, error) {
+				var item struct{ ID string }
+				err := client.Get(ctx, 0, []string{"items", key}, &item)
+				return item.ID, err
+			}})
+		}
+		return tasks
+	}
+
+	t.Run("partial failure", func(t *testing.T) {
+		var reports []api.BatchProgress
+		batch := &api.Batch{
+			Client:      client,
+			Concurrency: 2,
+			Progress:    func(progress api.BatchProgress) { reports = append(reports, progress) },
+		}
+		result, err := api.RunBatch(context.Background(), batch, newTasks("a", "b", "flaky", "missing", "c", "d")...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if len(result.Results) != 5 || result.Results["flaky"] != "flaky" {
+			t.Errorf("Expected 5 results, with the retried flaky one, got %v", result.Results)
+		}
+		var errResult api.ErrResult
+		if len(result.Errors) != 1 || !errors.As(result.Errors["missing"], &errResult) || !errResult.IsNotFound() {
+			t.Errorf("Expected a not found error for missing, got %v", result.Errors)
+		}
+		if attempts["missing"] != 1 || attempts["flaky"] != 2 {
+			t.Errorf("Expected missing to be tried once and flaky twice, got %v", attempts)
+		}
+		if maxRun > 2 {
+			t.Errorf("Expected at most 2 concurrent requests, got %d", maxRun)
+		}
+		if len(reports) != 6 || reports[5].Done != 6 || reports[5].Failed != 1 || reports[5].Total != 6 {
+			t.Errorf("Unexpected progress reports %+v", reports)
+		}
+	})
+
+	t.Run("fatal error", func(t *testing.T) {
+		keys := []string{"unauthorized"}
+		for i := 0; i < 20; i++ {
+			keys = append(keys, "item-"+string(rune('a'+i)))
+		}
+		result, err := api.RunBatch(context.Background(), &api.Batch{Client: client, Concurrency: 1}, newTasks(keys...)...)
+		var errResult api.ErrResult
+		if !errors.As(err, &errResult) || !errResult.IsUnauthorized() {
+			t.Fatalf("Expected an unauthorized error, got %v", err)
+		}
+		if attempts["unauthorized"] != 1 {
+			t.Errorf("Expected unauthorized to be tried once, got %d", attempts["unauthorized"])
+		}
+		if len(result.Skipped) != 20 || result.Skipped[0] != "item-a" {
+			t.Errorf("Expected the 20 other tasks to be skipped, got %v", result.Skipped)
+		}
+	})
+
+	t.Run("unlimited client policy", func(t *testing.T) {
+		client.Retry = &api.RetryPolicy{Wait: time.Millisecond}
+		defer func() { client.Retry = &api.RetryPolicy{MaxAttempts: 3, Wait: time.Millisecond} }()
+		result, err := api.RunBatch(context.Background(), &api.Batch{Client: client}, newTasks("down")...)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		var errResult api.ErrResult
+		if !errors.As(result.Errors["down"], &errResult) || !errResult.IsSystemIssue() {
+			t.Errorf("Expected a system issue error for down, got %v", result.Errors)
+		}
+		if attempts["down"] != api.DefaultBatchRetryPolicy.MaxAttempts {
+			t.Errorf("Expected down to be tried %d times, got %d", api.DefaultBatchRetryPolicy.MaxAttempts, attempts["down"])
+		}
+	})
+
+	t.Run("duplicate key", func(t *testing.T) {
+		if _, err := api.RunBatch(context.Background(), nil, newTasks("a", "a")...); err == nil {
+			t.Errorf("Expected an error for the duplicate key")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+)
+
+// DefaultMaxBufferedBody is the size of the largest body that is buffered, so it can be
+// sent again, when it cannot be rewound
+const DefaultMaxBufferedBody = 8 << 20
+
+var (
+	// ErrBodyNotReplayable is returned when a rate limited request cannot be retried
+	// because its body was consumed: it was neither seekable nor small enough to be buffered
+	ErrBodyNotReplayable = errors.New("request body cannot be replayed")
+	// ErrNotIdempotent is returned when a rate limited request is not retried because
+	// its operation is not idempotent, see Operation.IsIdempotent
+	ErrNotIdempotent = errors.New("operation is not idempotent")
+)
+
+// replayableBody is a request body that can be sent again
+type replayableBody struct {
+	seeker io.ReadSeeker
+	offset int64
+	data   []byte
+	// rest is the part of a body too large to be buffered, which makes it not replayable
+	rest io.Reader
+}
+
+// newReplayableBody returns body as a replayable one: seekable bodies are rewound, the
+// others are buffered up to limit bytes
+func newReplayableBody(body io.Reader, limit int64) (*replayableBody, error) {
+	if body == nil {
+		return nil, nil
+	}
+	if seeker, ok := body.(io.ReadSeeker); ok {
+		if offset, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			return &replayableBody{seeker: seeker, offset: offset}, nil
+		}
+	}
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return &replayableBody{data: data, rest: body}, nil
+	}
+	return &replayableBody{data: data}, nil
+}
+
+// reader returns the reader of the body to send
+func (b *replayableBody) reader() io.Reader {
+	switch {
+	case b == nil:
+		return nil
+	case b.seeker != nil:
+		if _, ok := b.seeker.(io.Closer); ok {
+			// net/http closes the bodies it sends, g.e. an *os.File, which is sent again
+			return struct{ io.Reader }{b.seeker}
+		}
+		return b.seeker
+	case b.rest != nil:
+		return io.MultiReader(bytes.NewReader(b.data), b.rest)
+	default:
+		return bytes.NewReader(b.data)
+	}
+}
+
+// replayable returns true if the body can be sent again
+func (b *replayableBody) replayable() bool { return b == nil || b.rest == nil }
+
+// rewind prepares the body to be sent again
+func (b *replayableBody) rewind() error {
+	switch {
+	case b == nil:
+		return nil
+	case b.rest != nil:
+		return ErrBodyNotReplayable
+	case b.seeker != nil:
+		_, err := b.seeker.Seek(b.offset, io.SeekStart)
+		return err
+	default:
+		return nil
+	}
+}
+
+// retrySafe returns true if a request with the method, of the operation of ctx, can be sent again
+func retrySafe(ctx context.Context, method string) bool {
+	op, _ := OperationFrom(ctx)
+	return op.Idempotent || idempotentMethod(method)
+}
@@ -0,0 +1,233 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBreakerFailures is the number of consecutive failures opening a circuit
+	DefaultBreakerFailures = 5
+	// DefaultBreakerOpenFor is how long a circuit stays open before it is probed
+	DefaultBreakerOpenFor = 30 * time.Second
+)
+
+// CircuitState is the state of the circuit of a service
+type CircuitState uint8
+
+const (
+	// CircuitClosed lets the requests through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails the requests with ErrCircuitOpen, without sending them
+	CircuitOpen
+	// CircuitHalfOpen lets one request through, to probe whether the service recovered
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrCircuitOpen is returned, without sending the request, while the circuit of the
+// service of the request is open
+type ErrCircuitOpen struct {
+	Service string
+	// Until is when the circuit is probed again
+	Until time.Time
+}
+
+func (err ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("circuit open for %v until %v", err.Service, err.Until.Format(time.RFC3339))
+}
+
+// CircuitEvent describes a change of state of the circuit of a service
+type CircuitEvent struct {
+	Service  string
+	From, To CircuitState
+	// Err is the failure that opened the circuit, nil when it closes or half-opens
+	Err  error
+	Time time.Time
+}
+
+// Service returns the service of the operation, the first part of its name: g.e. oss for
+// oss.objects.upload, or DefaultFamily for requests without an operation
+func Service(op Operation) string {
+	if op.Name == "" {
+		return DefaultFamily
+	}
+	service, _, _ := strings.Cut(op.Name, ".")
+	return service
+}
+
+// CircuitBreaker stops sending requests to a service that keeps failing, with a circuit per
+// service (see Service). A circuit opens after Failures consecutive server errors (5xx) or
+// timeouts; while open the requests fail fast with ErrCircuitOpen. After OpenFor it half-opens
+// and lets one request through: its success closes the circuit, its failure opens it again.
+//
+// The zero value is ready to use, and it is safe for concurrent use.
+type CircuitBreaker struct {
+	// Failures is the number of consecutive failures opening a circuit, if 0 DefaultBreakerFailures is used
+	Failures int
+	// OpenFor is how long a circuit stays open before it is probed, if 0 DefaultBreakerOpenFor is used
+	OpenFor time.Duration
+	// OnStateChange, if set, is called on each change of state of a circuit. It is called
+	// synchronously on the path of the request, so it should not block.
+	OnStateChange func(event CircuitEvent)
+
+	mutex    sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func (b *CircuitBreaker) openFor() time.Duration {
+	if b.OpenFor <= 0 {
+		return DefaultBreakerOpenFor
+	}
+	return b.OpenFor
+}
+
+// circuit returns the circuit of the service; the mutex must be held
+func (b *CircuitBreaker) circuit(service string) *circuit {
+	if b.circuits == nil {
+		b.circuits = make(map[string]*circuit)
+	}
+	c, ok := b.circuits[service]
+	if !ok {
+		c = new(circuit)
+		b.circuits[service] = c
+	}
+	return c
+}
+
+// transition changes the state of the circuit; the mutex must be held. The
+// event is returned, to be emitted once the mutex is released.
+func (b *CircuitBreaker) transition(service string, c *circuit, to CircuitState, err error) *CircuitEvent {
+	event := &CircuitEvent{Service: service, From: c.state, To: to, Err: err, Time: time.Now()}
+	c.state = to
+	c.probing = false
+	switch to {
+	case CircuitOpen:
+		c.openedAt = event.Time
+	case CircuitClosed:
+		c.failures = 0
+	}
+	return event
+}
+
+func (b *CircuitBreaker) emit(event *CircuitEvent) {
+	if event != nil && b.OnStateChange != nil {
+		b.OnStateChange(*event)
+	}
+}
+
+// State returns the current state of the circuit of the service
+func (b *CircuitBreaker) State(service string) CircuitState {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.circuit(service).state
+}
+
+// Allow returns an ErrCircuitOpen if a request to the service should not be sent.
+// Each allowed request must be followed by a call to Done.
+func (b *CircuitBreaker) Allow(service string) error {
+	var event *CircuitEvent
+	defer func() { b.emit(event) }()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	c := b.circuit(service)
+	if c.state == CircuitOpen {
+		until := c.openedAt.Add(b.openFor())
+		if time.Now().Before(until) {
+			return ErrCircuitOpen{Service: service, Until: until}
+		}
+		event = b.transition(service, c, CircuitHalfOpen, nil)
+	}
+	if c.state == CircuitHalfOpen {
+		if c.probing {
+			return ErrCircuitOpen{Service: service, Until: time.Now().Add(b.openFor())}
+		}
+		c.probing = true
+	}
+	return nil
+}
+
+// Done records the outcome of an allowed request to the service
+func (b *CircuitBreaker) Done(service string, res *http.Response, err error) {
+	var event *CircuitEvent
+	defer func() { b.emit(event) }()
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	c := b.circuit(service)
+	switch {
+	case err == nil && res != nil && res.StatusCode >= 500 && res.StatusCode <= 599:
+		err = ErrResult{StatusCode: res.StatusCode}
+	case isTimeout(err):
+	case err != nil:
+		// the request did not reach the service, or was canceled: no outcome
+		c.probing = false
+		return
+	default:
+		if c.state != CircuitClosed {
+			event = b.transition(service, c, CircuitClosed, nil)
+		}
+		c.failures = 0
+		return
+	}
+
+	c.failures++
+	failures := b.Failures
+	if failures <= 0 {
+		failures = DefaultBreakerFailures
+	}
+	if c.state == CircuitHalfOpen || (c.state == CircuitClosed && c.failures >= failures) {
+		event = b.transition(service, c, CircuitOpen, err)
+	}
+}
+
+// release records that an allowed request to the service was not sent
+func (b *CircuitBreaker) release(service string) {
+	b.mutex.Lock()
+	b.circuit(service).probing = false
+	b.mutex.Unlock()
+}
+
+// service returns the service of the operation of ctx
+func service(ctx context.Context) string {
+	op, _ := OperationFrom(ctx)
+	return Service(op)
+}
+
+// isTimeout returns true if err is a timeout
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,108 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		requests int
+		healthy  bool
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		requests++
+		if !healthy {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	setHealthy := func(value bool) {
+		mutex.Lock()
+		healthy = value
+		mutex.Unlock()
+	}
+
+	var transitions []string
+	breaker := &api.CircuitBreaker{
+		Failures: 2,
+		OpenFor:  50 * time.Millisecond,
+		OnStateChange: func(event api.CircuitEvent) {
+			transitions = append(transitions, event.From.String()+">"+event.To.String())
+		},
+	}
+	auth := static.New("token")
+	auth.Host = server.URL
+	client := api.NewClient(auth)
+	client.Breaker = breaker
+	ctx := api.Operation{Name: "md.manifest.get", Method: http.MethodGet}.Context(context.Background())
+	get := func() error { return client.Get(ctx, 0, []string{"modelderivative", "v2"}, nil) }
+
+	for i := 0; i < 2; i++ {
+		var errResult api.ErrResult
+		if err := get(); !errors.As(err, &errResult) || !errResult.IsSystemIssue() {
+			t.Fatalf("Expected a system issue, got %v", err)
+		}
+	}
+	if state := breaker.State("md"); state != api.CircuitOpen {
+		t.Fatalf("Expected the circuit to be open, got %v", state)
+	}
+
+	t.Run("Fail fast", func(t *testing.T) {
+		var errOpen api.ErrCircuitOpen
+		if err := get(); !errors.As(err, &errOpen) || errOpen.Service != "md" {
+			t.Errorf("Expected %T for md, got %v", errOpen, err)
+		}
+		mutex.Lock()
+		if requests != 2 {
+			t.Errorf("Expected the request not to be sent, got %d requests", requests)
+		}
+		mutex.Unlock()
+		if state := breaker.State("oss"); state != api.CircuitClosed {
+			t.Errorf("Expected the circuit of oss to be closed, got %v", state)
+		}
+	})
+
+	t.Run("Failed probe", func(t *testing.T) {
+		time.Sleep(60 * time.Millisecond)
+		if err := get(); err == nil {
+			t.Fatalf("Expected the probe to fail")
+		}
+		if state := breaker.State("md"); state != api.CircuitOpen {
+			t.Errorf("Expected the circuit to open again, got %v", state)
+		}
+	})
+
+	t.Run("Recovered", func(t *testing.T) {
+		setHealthy(true)
+		time.Sleep(60 * time.Millisecond)
+		if err := get(); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if state := breaker.State("md"); state != api.CircuitClosed {
+			t.Errorf("Expected the circuit to be closed, got %v", state)
+		}
+		expected := []string{
+			"closed>open",
+			"open>half-open", "half-open>open",
+			"open>half-open", "half-open>closed",
+		}
+		if !reflect.DeepEqual(transitions, expected) {
+			t.Errorf("Expected transitions %v, got %v", expected, transitions)
+		}
+	})
+}
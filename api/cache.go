@@ -0,0 +1,344 @@
+package api
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCacheEntries is the number of entries of an LRU cache created with 0 entries
+	DefaultCacheEntries = 1000
+	// DefaultMaxCachedBody is the size of the largest body cached
+	DefaultMaxCachedBody = 10 << 20
+)
+
+// CacheStatusHeader is the header set on the responses served by a Cache: CacheHit for
+// fresh entries served without a request, CacheRevalidated for entries Forge confirmed
+// were not modified.
+const CacheStatusHeader = "X-Forge-Cache"
+
+const (
+	CacheHit         = "hit"
+	CacheRevalidated = "revalidated"
+)
+
+// CacheEntry is a cached response
+type CacheEntry struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	// StoredAt is when the response was received, or last revalidated
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// CacheBackend stores the entries of a Cache; implementations must be safe for concurrent use
+type CacheBackend interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+	Delete(key string)
+}
+
+// Cache caches the responses to GET requests, revalidating them with If-None-Match and
+// If-Modified-Since. Responses with Cache-Control no-store are not cached, the ones with
+// a max-age are served without a request while they are fresh, the others are revalidated
+// on each request.
+//
+// The entries are keyed by url, query and identity; the default identity is the
+// Authorization header (and the user the app acts for), so entries are never shared
+// between tokens.
+type Cache struct {
+	Backend CacheBackend
+	// Identity returns the identity of the request, if nil the Authorization and
+	// x-user-id headers are used
+	Identity func(req *http.Request) string
+	// MaxBodySize is the size of the largest body cached, if 0 DefaultMaxCachedBody is used
+	MaxBodySize int64
+}
+
+// NewCache returns a cache using an LRU backend with the given number of entries
+func NewCache(entries int) *Cache {
+	return &Cache{Backend: NewLRUCache(entries)}
+}
+
+// Transport returns a RoundTripper caching the responses got through base; if base is nil
+// http.DefaultTransport is used
+func (c *Cache) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return cacheTransport{cache: c, base: base}
+}
+
+func (c *Cache) key(req *http.Request) string {
+	identity := req.Header.Get("Authorization") + "\n" + req.Header.Get("x-user-id")
+	if c.Identity != nil {
+		identity = c.Identity(req)
+	}
+	sum := sha256.Sum256([]byte(identity + "\n" + req.URL.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Cache) maxBodySize() int64 {
+	if c.MaxBodySize <= 0 {
+		return DefaultMaxCachedBody
+	}
+	return c.MaxBodySize
+}
+
+type cacheTransport struct {
+	cache *Cache
+	base  http.RoundTripper
+}
+
+func (t cacheTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("Range") != "" || t.cache.Backend == nil {
+		return t.base.RoundTrip(req)
+	}
+	key := t.cache.key(req)
+	entry, cached := t.cache.Backend.Get(key)
+	if cached {
+		if !hasDirective(req.Header, "no-cache") && entry.fresh(time.Now()) {
+			return entry.response(req, CacheHit), nil
+		}
+		req = req.Clone(req.Context())
+		if etag := entry.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		if modified := entry.Header.Get("Last-Modified"); modified != "" {
+			req.Header.Set("If-Modified-Since", modified)
+		}
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if cached && res.StatusCode == http.StatusNotModified {
+		io.Copy(io.Discard, res.Body)
+		res.Body.Close()
+		updated := *entry
+		updated.Header = entry.Header.Clone()
+		for _, name := range []string{"Cache-Control", "Date", "ETag", "Expires", "Last-Modified"} {
+			if value := res.Header.Get(name); value != "" {
+				updated.Header.Set(name, value)
+			}
+		}
+		updated.StoredAt = time.Now()
+		t.cache.Backend.Set(key, &updated)
+		return updated.response(req, CacheRevalidated), nil
+	}
+	if res.StatusCode != http.StatusOK || !storable(res.Header) {
+		if cached {
+			t.cache.Backend.Delete(key)
+		}
+		return res, nil
+	}
+	if res.ContentLength > t.cache.maxBodySize() {
+		return res, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, t.cache.maxBodySize()+1))
+	if err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+	if int64(len(body)) > t.cache.maxBodySize() {
+		// too large, the rest of the body is read from the network
+		res.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), res.Body), Closer: res.Body}
+		return res, nil
+	}
+	res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	t.cache.Backend.Set(key, &CacheEntry{
+		StatusCode: res.StatusCode,
+		Header:     res.Header.Clone(),
+		Body:       body,
+		StoredAt:   time.Now(),
+	})
+	return res, nil
+}
+
+// hasDirective returns true if the Cache-Control header has the directive
+func hasDirective(header http.Header, directive string) bool {
+	_, ok := cacheControl(header)[directive]
+	return ok
+}
+
+// cacheControl returns the directives of the Cache-Control header
+func cacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, directive := range strings.Split(value, ",") {
+			name, arg, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			directives[strings.ToLower(name)] = strings.Trim(arg, `"`)
+		}
+	}
+	return directives
+}
+
+// storable returns true if the response can be cached and revalidated
+func storable(header http.Header) bool {
+	directives := cacheControl(header)
+	if _, ok := directives["no-store"]; ok {
+		return false
+	}
+	_, maxAge := directives["max-age"]
+	return maxAge || header.Get("ETag") != "" || header.Get("Last-Modified") != ""
+}
+
+// fresh returns true if the entry can be served without revalidation
+func (entry *CacheEntry) fresh(now time.Time) bool {
+	directives := cacheControl(entry.Header)
+	if _, ok := directives["no-cache"]; ok {
+		return false
+	}
+	seconds, err := strconv.Atoi(directives["max-age"])
+	if err != nil || seconds <= 0 {
+		return false
+	}
+	return now.Before(entry.StoredAt.Add(time.Duration(seconds) * time.Second))
+}
+
+// response returns the entry as the response to req
+func (entry *CacheEntry) response(req *http.Request, status string) *http.Response {
+	header := entry.Header.Clone()
+	header.Set(CacheStatusHeader, status)
+	return &http.Response{
+		Status:        strconv.Itoa(entry.StatusCode) + " " + http.StatusText(entry.StatusCode),
+		StatusCode:    entry.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(entry.Body)),
+		ContentLength: int64(len(entry.Body)),
+		Request:       req,
+	}
+}
+
+// LRUCache is an in-memory CacheBackend evicting the least recently used entries
+type LRUCache struct {
+	mutex   sync.Mutex
+	max     int
+	order   *list.List // front is the most recently used
+	entries map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	entry *CacheEntry
+}
+
+// NewLRUCache returns an LRU backend keeping up to entries entries, if 0 DefaultCacheEntries
+func NewLRUCache(entries int) *LRUCache {
+	if entries <= 0 {
+		entries = DefaultCacheEntries
+	}
+	return &LRUCache{max: entries, order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+func (c *LRUCache) Get(key string) (*CacheEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruItem).entry, true
+}
+
+func (c *LRUCache) Set(key string, entry *CacheEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruItem{key: key, entry: entry})
+	for c.order.Len() > c.max {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruItem).key)
+	}
+}
+
+func (c *LRUCache) Delete(key string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of entries
+func (c *LRUCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.order.Len()
+}
+
+// DiskCache is a CacheBackend storing each entry in a file of Dir. Failing reads are
+// misses and failing writes are ignored, so a broken cache only costs requests.
+type DiskCache struct {
+	Dir string
+}
+
+// NewDiskCache returns a disk backend storing its entries in dir, which is created if needed
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &DiskCache{Dir: dir}, nil
+}
+
+func (c *DiskCache) path(key string) string { return filepath.Join(c.Dir, key+".json") }
+
+func (c *DiskCache) Get(key string) (*CacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry CacheEntry
+	if err = json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+	return &entry, true
+}
+
+func (c *DiskCache) Set(key string, entry *CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	// written to a temporary file first, so readers never see a partial entry
+	file, err := os.CreateTemp(c.Dir, key+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), c.path(key))
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+}
+
+func (c *DiskCache) Delete(key string) { os.Remove(c.path(key)) }
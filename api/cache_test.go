@@ -0,0 +1,131 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+func TestCache(t *testing.T) {
+	var (
+		mutex sync.Mutex
+		// requests are the requests received by path, conditional ones are prefixed with ?
+		requests []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		if r.Header.Get("If-None-Match") != "" {
+			requests = append(requests, "?"+r.URL.Path)
+		} else {
+			requests = append(requests, r.URL.Path)
+		}
+		mutex.Unlock()
+		switch r.URL.Path {
+		case "/fresh":
+			w.Header().Set("Cache-Control", "private, max-age=60")
+		case "/private":
+			w.Header().Set("Cache-Control", "no-store")
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"path":"` + r.URL.Path + `"}`))
+	}))
+	defer server.Close()
+
+	newClient := func(token string, cache *api.Cache) *api.Client {
+		auth := static.New(token)
+		auth.Host = server.URL
+		client := api.NewClient(auth)
+		client.Cache = cache
+		return client
+	}
+	get := func(t *testing.T, client *api.Client, path string) (status string) {
+		t.Helper()
+		var (
+			result map[string]string
+			meta   api.ResponseMeta
+		)
+		ctx := api.WithCallOptions(context.Background(), api.WithResponseMeta(&meta))
+		if err := client.Get(ctx, 0, []string{path}, &result); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if result["path"] != "/"+path {
+			t.Errorf("Expected the body of /%v, got %v", path, result)
+		}
+		return meta.Header.Get(api.CacheStatusHeader)
+	}
+	expectRequests := func(t *testing.T, expected ...string) {
+		t.Helper()
+		mutex.Lock()
+		defer mutex.Unlock()
+		if len(requests) != len(expected) {
+			t.Fatalf("Expected requests %v, got %v", expected, requests)
+		}
+		for i := range expected {
+			if requests[i] != expected[i] {
+				t.Fatalf("Expected requests %v, got %v", expected, requests)
+			}
+		}
+		requests = nil
+	}
+
+	backends := map[string]func(t *testing.T) api.CacheBackend{
+		"LRU": func(t *testing.T) api.CacheBackend { return api.NewLRUCache(10) },
+		"Disk": func(t *testing.T) api.CacheBackend {
+			backend, err := api.NewDiskCache(t.TempDir())
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+			return backend
+		},
+	}
+	for name, backend := range backends {
+		t.Run(name, func(t *testing.T) {
+			cache := &api.Cache{Backend: backend(t)}
+			client := newClient("token", cache)
+
+			if status := get(t, client, "hubs"); status != "" {
+				t.Errorf("Expected a miss, got %v", status)
+			}
+			if status := get(t, client, "hubs"); status != api.CacheRevalidated {
+				t.Errorf("Expected %v, got %v", api.CacheRevalidated, status)
+			}
+			expectRequests(t, "/hubs", "?/hubs")
+
+			// entries are never shared between tokens
+			get(t, newClient("other-token", cache), "hubs")
+			expectRequests(t, "/hubs")
+
+			get(t, client, "fresh")
+			if status := get(t, client, "fresh"); status != api.CacheHit {
+				t.Errorf("Expected %v, got %v", api.CacheHit, status)
+			}
+			expectRequests(t, "/fresh")
+
+			get(t, client, "private")
+			get(t, client, "private")
+			expectRequests(t, "/private", "/private")
+		})
+	}
+
+	t.Run("LRU eviction", func(t *testing.T) {
+		lru := api.NewLRUCache(2)
+		for _, key := range []string{"a", "b", "c"} {
+			lru.Set(key, &api.CacheEntry{StatusCode: http.StatusOK})
+		}
+		if _, ok := lru.Get("a"); ok {
+			t.Errorf("Expected the least recently used entry to be evicted")
+		}
+		if lru.Len() != 2 {
+			t.Errorf("Expected 2 entries, got %d", lru.Len())
+		}
+	})
+}
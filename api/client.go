@@ -29,6 +29,21 @@ type Filterer interface {
 type Client struct {
 	Client http.Client
 	oauth.ForgeAuthenticator
+	// Retry controls how rate limited requests are retried, if nil DefaultRetryPolicy is used.
+	// With a RateLimit, the limiter paces the retries and the policy only limits their attempts.
+	Retry *RetryPolicy
+	// Log, if set, logs the requests and responses, see Logging
+	Log *Logging
+	// Metrics, if set, records the latency, errors and retries of the requests, see Metrics
+	Metrics Metrics
+	// RateLimit, if set, throttles the requests before Forge rate limits them, see RateLimiter
+	RateLimit *RateLimiter
+	// Breaker, if set, fails the requests fast while their service is failing, see CircuitBreaker
+	Breaker *CircuitBreaker
+	// Cache, if set, caches the responses to GET requests, see Cache
+	Cache *Cache
+	// DryRun, if set, captures the requests instead of sending them, see DryRun
+	DryRun *DryRun
 }
 
 func NewClient(auth oauth.ForgeAuthenticator) *Client {
@@ -44,6 +59,12 @@ func (c *Client) DoRawRequest(ctx context.Context, method string, scope scopes.S
 	)
 	if c != nil {
 		client = c.Client
+		if c.Log != nil {
+			client.Transport = c.Log.Transport(client.Transport)
+		}
+		if c.Cache != nil {
+			client.Transport = c.Cache.Transport(client.Transport)
+		}
 		if c.ForgeAuthenticator != nil {
 			auth = c.ForgeAuthenticator
 		}
@@ -75,14 +96,52 @@ func (c *Client) DoRawRequest(ctx context.Context, method string, scope scopes.S
 	if contentType != "" {
 		req.Header.Set("Content-Type", contentType)
 	}
+	for key, values := range CallOptionsFrom(ctx).Header {
+		req.Header[key] = values
+	}
 	if setHeaders != nil {
 		setHeaders(req.Header)
 	}
-	if err := auth.SetAuthHeader(scope, req.Header); err != nil {
+	if c.dryRunning() {
+		res, err := c.DryRun.capture(ctx, scope, req)
+		setResponseMeta(ctx, res)
+		return res, err
+	}
+	if err := oauth.SetAuthHeader(ctx, auth, scope, req.Header); err != nil {
 		return nil, fmt.Errorf("DoRawRequest:%w", err)
 	}
 
-	return client.Do(req)
+	var (
+		limiter *RateLimiter
+		breaker *CircuitBreaker
+	)
+	if c != nil {
+		limiter, breaker = c.RateLimit, c.Breaker
+	}
+	if breaker != nil {
+		if err := breaker.Allow(service(ctx)); err != nil {
+			return nil, err
+		}
+	}
+	if limiter != nil {
+		if err := limiter.Wait(ctx, family(ctx)); err != nil {
+			if breaker != nil {
+				breaker.release(service(ctx))
+			}
+			return nil, err
+		}
+	}
+	start := time.Now()
+	res, err := client.Do(req)
+	c.observeRequest(ctx, start, res, err)
+	setResponseMeta(ctx, res)
+	if limiter != nil {
+		limiter.Observe(family(ctx), res)
+	}
+	if breaker != nil {
+		breaker.Done(service(ctx), res, err)
+	}
+	return res, err
 }
 
 func (c *Client) ProcessRawError(response *http.Response, result interface{}) (err error) {
@@ -104,9 +163,10 @@ func (c *Client) ProcessRawError(response *http.Response, result interface{}) (e
 func (c *Client) ProcessJSONError(response *http.Response, result interface{}) (err error) {
 	decoder := json.NewDecoder(response.Body)
 	if response.StatusCode != http.StatusOK {
-		err = ErrResult{StatusCode: response.StatusCode}
-		_ = decoder.Decode(&err)
-		return err
+		// decoding into err itself would replace it with nil
+		errResult := ErrResult{StatusCode: response.StatusCode}
+		_ = decoder.Decode(&errResult)
+		return errResult
 	}
 	if result == nil {
 		return nil
@@ -119,9 +179,19 @@ func (c *Client) ProcessJSONError(response *http.Response, result interface{}) (
 }
 
 func (c *Client) DoRequest(ctx context.Context, method string, scope scopes.Scope, paths []string, result interface{}, filters []Filterer, contentType string, body io.Reader) error {
+	policy := DefaultRetryPolicy
+	if c != nil && c.Retry != nil {
+		policy = *c.Retry
+	}
+	attempt := 0
+	replay, err := newReplayableBody(body, policy.maxBufferedBody())
+	if err != nil {
+		return fmt.Errorf("error reading the body of %v %v : %w", method, strings.Join(paths, "/"), err)
+	}
 
 START:
-	res, err := c.DoRawRequest(ctx, method, scope, paths, filters, nil, contentType, body)
+	attempt++
+	res, err := c.DoRawRequest(ctx, method, scope, paths, filters, nil, contentType, replay.reader())
 	if err != nil {
 		return fmt.Errorf("error making request to %v %v : %w", method, strings.Join(paths, "/"), err)
 	}
@@ -132,7 +202,26 @@ START:
 		switch {
 		case errResult.IsRateLimited():
 			// we need to wait for a bit and then retry
-			<-time.After(30 * time.Second)
+			wait, ok := policy.retry(attempt, res.Header)
+			if !ok {
+				return errResult
+			}
+			if !retrySafe(ctx, method) {
+				return fmt.Errorf("%w, not retrying %v %v: %w", ErrNotIdempotent, method, strings.Join(paths, "/"), errResult)
+			}
+			if !replay.replayable() {
+				return fmt.Errorf("%w, not retrying %v %v: %w", ErrBodyNotReplayable, method, strings.Join(paths, "/"), errResult)
+			}
+			// a rate limiter already paused the family for the retry, see RateLimiter.Observe
+			if c == nil || c.RateLimit == nil {
+				if err := sleep(ctx, wait); err != nil {
+					return err
+				}
+			}
+			if err := replay.rewind(); err != nil {
+				return fmt.Errorf("%w, not retrying %v %v: %w", ErrBodyNotReplayable, method, strings.Join(paths, "/"), err)
+			}
+			c.observeRetry(ctx)
 			goto START
 		case errResult.StatusCode == http.StatusUnsupportedMediaType:
 			// This is lke a 500 error, however something is wrong with
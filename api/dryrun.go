@@ -0,0 +1,250 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// DefaultBodyPreview is the number of bytes of the bodies kept by a DryRun
+const DefaultBodyPreview = 4096
+
+// CapturedRequest is a request captured by a DryRun
+type CapturedRequest struct {
+	// Operation is the name of the operation, empty if the request had none
+	Operation string
+	Method    string
+	// URL with the filters applied, and the secrets redacted
+	URL string
+	// Header with the Authorization redacted
+	Header http.Header
+	// Scope the request would have been authenticated with
+	Scope scopes.Scope
+	Body  BodySummary
+}
+
+// BodySummary describes the body of a captured request
+type BodySummary struct {
+	ContentType string
+	// Size in bytes, 0 for requests without a body
+	Size int64
+	// Preview is the beginning of a text body, redacted; empty for binary bodies
+	Preview string
+	// Truncated is true if the body is longer than the preview
+	Truncated bool
+}
+
+// SyntheticResponse is the response a DryRun returns to a request
+type SyntheticResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+}
+
+// DryRun captures the requests of a Client instead of sending them, answering them with
+// synthetic responses. Requests are not authenticated, so no credentials are needed. g.e.
+//
+//	dryRun := new(api.DryRun)
+//	client.DryRun = dryRun
+//	// ... run the script ...
+//	dryRun.WriteCurl(os.Stdout)
+type DryRun struct {
+	// Respond, if set, returns the response to a captured request; by default
+	// it is a 200 with an empty JSON object.
+	Respond func(req CapturedRequest) SyntheticResponse
+	// MaxPreview is the number of bytes of the bodies kept, if 0 DefaultBodyPreview is used
+	MaxPreview int
+
+	mutex    sync.Mutex
+	requests []CapturedRequest
+}
+
+// Requests returns the captured requests, in the order they were made
+func (d *DryRun) Requests() []CapturedRequest {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return append([]CapturedRequest(nil), d.requests...)
+}
+
+// Reset forgets the captured requests
+func (d *DryRun) Reset() {
+	d.mutex.Lock()
+	d.requests = nil
+	d.mutex.Unlock()
+}
+
+func (d *DryRun) maxPreview() int {
+	if d.MaxPreview <= 0 {
+		return DefaultBodyPreview
+	}
+	return d.MaxPreview
+}
+
+// capture records req, which would have been authenticated for scope, and returns its synthetic response
+func (d *DryRun) capture(ctx context.Context, scope scopes.Scope, req *http.Request) (*http.Response, error) {
+	captured := CapturedRequest{
+		Method: req.Method,
+		URL:    RedactURL(req.URL),
+		Header: RedactHeader(req.Header),
+		Scope:  scope,
+	}
+	captured.Header.Set("Authorization", "Bearer "+oauth.Redacted)
+	if op, ok := OperationFrom(ctx); ok {
+		captured.Operation = op.Name
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		summary, err := d.summarize(req.Body, req.Header.Get("Content-Type"))
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		captured.Body = summary
+	}
+	d.mutex.Lock()
+	d.requests = append(d.requests, captured)
+	d.mutex.Unlock()
+
+	synthetic := SyntheticResponse{StatusCode: http.StatusOK, Body: "{}"}
+	if d.Respond != nil {
+		synthetic = d.Respond(captured)
+	}
+	header := synthetic.Header.Clone()
+	if header == nil {
+		header = http.Header{"Content-Type": []string{ContentTypeJSON}}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", synthetic.StatusCode, http.StatusText(synthetic.StatusCode)),
+		StatusCode:    synthetic.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(synthetic.Body)),
+		ContentLength: int64(len(synthetic.Body)),
+		Request:       req,
+	}, nil
+}
+
+// summarize reads the body, keeping its beginning
+func (d *DryRun) summarize(body io.Reader, contentType string) (BodySummary, error) {
+	head := make([]byte, d.maxPreview())
+	n, err := io.ReadFull(body, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return BodySummary{}, err
+	}
+	rest, err := io.Copy(io.Discard, body)
+	if err != nil {
+		return BodySummary{}, err
+	}
+	summary := BodySummary{ContentType: contentType, Size: int64(n) + rest, Truncated: rest > 0}
+	head = head[:n]
+	if summary.Truncated {
+		head = trimPartialRune(head)
+	}
+	binary := strings.HasPrefix(contentType, "application/octet-stream") || bytes.IndexByte(head, 0) >= 0
+	if !binary && utf8.Valid(head) {
+		summary.Preview = RedactBody(string(head), contentType)
+	}
+	return summary, nil
+}
+
+// trimPartialRune removes the incomplete rune a truncated text may end with
+func trimPartialRune(data []byte) []byte {
+	for i := 0; i < utf8.UTFMax && len(data) > 0 && !utf8.Valid(data); i++ {
+		data = data[:len(data)-1]
+	}
+	return data
+}
+
+// headerNames returns the names of the header, sorted
+func headerNames(header http.Header) []string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// WriteCurl writes the captured requests as curl commands. Bodies that were truncated,
+// or are binary, are replaced by a file to provide.
+func (d *DryRun) WriteCurl(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	for i, req := range d.Requests() {
+		if i > 0 {
+			out.WriteString("\n")
+		}
+		if req.Operation != "" {
+			fmt.Fprintf(out, "# %s\n", req.Operation)
+		}
+		fmt.Fprintf(out, "curl -X %s %s", req.Method, shellQuote(req.URL))
+		for _, name := range headerNames(req.Header) {
+			for _, value := range req.Header[name] {
+				fmt.Fprintf(out, " \\\n  -H %s", shellQuote(name+": "+value))
+			}
+		}
+		switch {
+		case req.Body.Size == 0:
+		case req.Body.Preview != "" && !req.Body.Truncated:
+			fmt.Fprintf(out, " \\\n  --data-binary %s", shellQuote(req.Body.Preview))
+		default:
+			fmt.Fprintf(out, " \\\n  --data-binary @body-%d # %d bytes", i+1, req.Body.Size)
+		}
+		out.WriteString("\n")
+	}
+	return out.Flush()
+}
+
+// WriteHTTPFile writes the captured requests as an HTTP file, as used by the REST
+// clients of editors. Bodies that were truncated, or are binary, are replaced by a
+// file to provide.
+func (d *DryRun) WriteHTTPFile(w io.Writer) error {
+	out := bufio.NewWriter(w)
+	for i, req := range d.Requests() {
+		title := req.Operation
+		if title == "" {
+			title = req.Method + " " + urlPath(req.URL)
+		}
+		fmt.Fprintf(out, "### %s\n%s %s\n", title, req.Method, req.URL)
+		for _, name := range headerNames(req.Header) {
+			for _, value := range req.Header[name] {
+				fmt.Fprintf(out, "%s: %s\n", name, value)
+			}
+		}
+		switch {
+		case req.Body.Size == 0:
+		case req.Body.Preview != "" && !req.Body.Truncated:
+			fmt.Fprintf(out, "\n%s\n", strings.TrimRight(req.Body.Preview, "\n"))
+		default:
+			fmt.Fprintf(out, "\n< ./body-%d\n", i+1)
+		}
+		out.WriteString("\n")
+	}
+	return out.Flush()
+}
+
+func urlPath(raw string) string {
+	if u, err := url.Parse(raw); err == nil {
+		return u.Path
+	}
+	return raw
+}
+
+// dryRunning returns true if the requests of the client are captured
+func (c *Client) dryRunning() bool { return c != nil && c.DryRun != nil }
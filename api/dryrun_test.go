@@ -0,0 +1,91 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/twolegged"
+)
+
+func TestDryRun(t *testing.T) {
+	// the host does not resolve: nothing, not even the authentication, may be sent
+	auth := twolegged.NewAuth("client", "secret")
+	auth.Host = "https://forge.invalid"
+	client := api.NewClient(auth)
+	dryRun := &api.DryRun{MaxPreview: 16}
+	client.DryRun = dryRun
+
+	create := api.Operation{Name: "oss.buckets.create", Method: http.MethodPost}
+	if err := client.Post(create.Context(context.Background()), scopes.BucketCreate, []string{"oss", "v2", "buckets"}, nil,
+		api.ContentTypeJSON, strings.NewReader(`{"bucketKey":"b"}`[:16])); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	upload := api.Operation{Name: "oss.objects.upload", Method: http.MethodPut}
+	if err := client.Put(upload.Context(context.Background()), scopes.DataWrite, []string{"oss", "v2", "buckets", "b", "objects", "o"}, nil,
+		"application/octet-stream", bytes.NewReader(make([]byte, 100))); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	limit := filterFunc(func(values url.Values) error {
+		values.Set("limit", "10")
+		return nil
+	})
+	var result map[string]interface{}
+	if err := client.Get(context.Background(), scopes.BucketRead, []string{"oss", "v2", "buckets"}, &result, limit); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+
+	requests := dryRun.Requests()
+	if len(requests) != 3 {
+		t.Fatalf("Expected 3 captured requests, got %d", len(requests))
+	}
+	if requests[0].Operation != "oss.buckets.create" || requests[0].Scope != scopes.BucketCreate || requests[0].Body.Preview != `{"bucketKey":"b"` {
+		t.Errorf("Unexpected create request %+v", requests[0])
+	}
+	if body := requests[1].Body; body.Size != 100 || body.Preview != "" || !body.Truncated {
+		t.Errorf("Expected a summary of the 100 bytes binary body, got %+v", body)
+	}
+	if !strings.HasSuffix(requests[2].URL, "/oss/v2/buckets?limit=10") {
+		t.Errorf("Expected the filters in the url, got %v", requests[2].URL)
+	}
+
+	t.Run("curl", func(t *testing.T) {
+		var out strings.Builder
+		if err := dryRun.WriteCurl(&out); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		for _, expected := range []string{
+			"# oss.buckets.create\ncurl -X POST 'https://forge.invalid",
+			"-H 'Authorization: Bearer " + oauth.Redacted + "'",
+			`--data-binary '{"bucketKey":"b"'`,
+			"--data-binary @body-2 # 100 bytes",
+		} {
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("Expected %q in:\n%s", expected, out.String())
+			}
+		}
+	})
+
+	t.Run("HTTP file", func(t *testing.T) {
+		var out strings.Builder
+		if err := dryRun.WriteHTTPFile(&out); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		for _, expected := range []string{
+			"### oss.objects.upload\nPUT https://forge.invalid",
+			"Content-Type: application/octet-stream\n",
+			"\n< ./body-2\n",
+			"### GET /oss/v2/buckets\n",
+		} {
+			if !strings.Contains(out.String(), expected) {
+				t.Errorf("Expected %q in:\n%s", expected, out.String())
+			}
+		}
+	})
+}
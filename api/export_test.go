@@ -0,0 +1,8 @@
+package api
+
+// UnregisterOperation removes the operation from the registry, so tests can register it again
+func UnregisterOperation(name string) {
+	operations.Lock()
+	delete(operations.byName, name)
+	operations.Unlock()
+}
@@ -0,0 +1,338 @@
+package filters
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrEmptyName is returned for filters without a field name
+var ErrEmptyName = errors.New("filter without a field name")
+
+// Kind is the kind of the values of a field, it decides the comparisons the field accepts
+type Kind uint8
+
+const (
+	// KindString fields accept equality, starts, ends and contains
+	KindString Kind = iota
+	// KindNumber fields accept equality and ordering, with numeric values
+	KindNumber
+	// KindDate fields accept equality and ordering, with RFC 3339 or 2006-01-02 values
+	KindDate
+	// KindBool fields accept equality with true or false
+	KindBool
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindString:
+		return "string"
+	case KindNumber:
+		return "number"
+	case KindDate:
+		return "date"
+	case KindBool:
+		return "bool"
+	default:
+		return "unknown"
+	}
+}
+
+// accepts returns true if the comparison is valid for the kind
+func (k Kind) accepts(c Comparison) bool {
+	switch c {
+	case ComparisonNone, ComparisonEqual:
+		return true
+	case ComparisonLess, ComparisonLessOrEqual, ComparisonGreater, ComparisonGreaterOrEqual:
+		return k == KindNumber || k == KindDate
+	case ComparisonStartsWith, ComparisonEndsWith, ComparisonContains:
+		return k == KindString
+	default:
+		return false
+	}
+}
+
+// check returns an error if value is not a value of the kind
+func (k Kind) check(value string) error {
+	var err error
+	switch k {
+	case KindNumber:
+		_, err = strconv.ParseFloat(value, 64)
+	case KindBool:
+		_, err = strconv.ParseBool(value)
+	case KindDate:
+		if _, err = time.Parse(time.RFC3339, value); err != nil {
+			_, err = time.Parse("2006-01-02", value)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%q is not a %v", value, k)
+	}
+	return nil
+}
+
+// Fields are the fields an endpoint can be filtered on, with their kind
+type Fields map[string]Kind
+
+// The fields of the Data Management endpoints
+var (
+	HubFields = Fields{
+		"id":             KindString,
+		"name":           KindString,
+		"extension.type": KindString,
+	}
+	ProjectFields = Fields{
+		"id":             KindString,
+		"name":           KindString,
+		"extension.type": KindString,
+	}
+	FolderContentFields = Fields{
+		"type":                   KindString,
+		"id":                     KindString,
+		"name":                   KindString,
+		"displayName":            KindString,
+		"extension.type":         KindString,
+		"mimeType":               KindString,
+		"hidden":                 KindBool,
+		"versionNumber":          KindNumber,
+		"createTime":             KindDate,
+		"lastModifiedTime":       KindDate,
+		"lastModifiedTimeRollup": KindDate,
+	}
+	ItemVersionFields = Fields{
+		"type":             KindString,
+		"id":               KindString,
+		"name":             KindString,
+		"displayName":      KindString,
+		"extension.type":   KindString,
+		"mimeType":         KindString,
+		"versionNumber":    KindNumber,
+		"createTime":       KindDate,
+		"lastModifiedTime": KindDate,
+	}
+)
+
+// ValidationError is returned for a filter that is not valid for the fields of an endpoint
+type ValidationError struct {
+	Filter Filter
+	Reason string
+}
+
+func (err ValidationError) Error() string {
+	return fmt.Sprintf("invalid filter %v%v: %v", err.Filter.Name, err.Filter.Comparison, err.Reason)
+}
+
+// Validate checks the filter has a name, and a comparison and values valid for the fields;
+// with nil fields any name is accepted, as a string.
+func (filter Filter) Validate(fields Fields) error {
+	if filter.Name == "" {
+		return ErrEmptyName
+	}
+	kind := KindString
+	if fields != nil {
+		var ok bool
+		if kind, ok = fields[filter.Name]; !ok {
+			return ValidationError{Filter: filter, Reason: "unknown field"}
+		}
+	}
+	if filter.Comparison.String() == "" && filter.Comparison != ComparisonNone {
+		return ValidationError{Filter: filter, Reason: "unknown comparison"}
+	}
+	if !kind.accepts(filter.Comparison) {
+		return ValidationError{Filter: filter, Reason: fmt.Sprintf("comparison not valid for a %v field", kind)}
+	}
+	if len(filter.Values) == 0 {
+		return ValidationError{Filter: filter, Reason: "no value"}
+	}
+	for _, value := range filter.Values {
+		if err := kind.check(value); err != nil {
+			return ValidationError{Filter: filter, Reason: err.Error()}
+		}
+	}
+	return nil
+}
+
+// Filters are validated filters, see Builder and Parse. The Data Management filters
+// validate them again, for the fields of their endpoint, when they are added.
+type Filters []Filter
+
+// Validate checks all the filters are valid for the fields, see Filter.Validate
+func (filters Filters) Validate(fields Fields) error {
+	for _, filter := range filters {
+		if err := filter.Validate(fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (filters Filters) Add(values url.Values) error {
+	for _, filter := range filters {
+		if err := filter.Add(values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Builder builds validated filters for the fields of an endpoint. g.e.
+//
+//	filter, err := filters.For(filters.ItemVersionFields).
+//		Where("name", filters.ComparisonContains, "plan").
+//		Where("versionNumber", filters.ComparisonGreaterOrEqual, "3").
+//		Build()
+type Builder struct {
+	fields  Fields
+	filters Filters
+	err     error
+}
+
+// For returns a builder for the fields
+func For(fields Fields) *Builder {
+	return &Builder{fields: fields}
+}
+
+// Where adds a filter on the field; the first invalid filter is returned by Build
+func (b *Builder) Where(name string, comparison Comparison, values ...string) *Builder {
+	filter := Filter{Name: name, Comparison: comparison, Values: values}
+	if err := filter.Validate(b.fields); err != nil && b.err == nil {
+		b.err = err
+	}
+	b.filters = append(b.filters, filter)
+	return b
+}
+
+// Build returns the filters, or the error of the first invalid one
+func (b *Builder) Build() (Filters, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	return b.filters, nil
+}
+
+// ParseComparison returns the comparison of its suffix, g.e. -ge; "" is ComparisonNone
+func ParseComparison(suffix string) (Comparison, error) {
+	for c := ComparisonNone; c <= ComparisonContains; c++ {
+		if c.String() == suffix {
+			return c, nil
+		}
+	}
+	return ComparisonNone, fmt.Errorf("unknown comparison %q", suffix)
+}
+
+// Parse returns the validated filters of an expression: terms joined by AND, each
+// term being a field, an optional comparison suffix, a colon and comma separated values.
+// Values with spaces or commas are double quoted. g.e.
+//
+//	name-contains:plan AND versionNumber-ge:3 AND extension.type:"a,b"
+func Parse(expr string, fields Fields) (Filters, error) {
+	terms, err := splitTerms(expr)
+	if err != nil {
+		return nil, err
+	}
+	b := For(fields)
+	for _, term := range terms {
+		key, rawValues, ok := strings.Cut(term, ":")
+		if !ok {
+			return nil, fmt.Errorf("filter %q: expected field:value", term)
+		}
+		name, comparison := key, ComparisonNone
+		if i := strings.LastIndex(key, "-"); i >= 0 {
+			if comparison, err = ParseComparison(key[i:]); err != nil {
+				return nil, fmt.Errorf("filter %q: %w", term, err)
+			}
+			name = key[:i]
+		}
+		values, err := splitValues(rawValues)
+		if err != nil {
+			return nil, fmt.Errorf("filter %q: %w", term, err)
+		}
+		b.Where(name, comparison, values...)
+	}
+	return b.Build()
+}
+
+// splitTerms splits the expression on the AND keywords outside quotes
+func splitTerms(expr string) (terms []string, err error) {
+	// the words are separated by the whitespace outside quotes, which is kept verbatim
+	var (
+		words  []string
+		word   strings.Builder
+		quoted bool
+	)
+	for _, r := range expr {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case !quoted && unicode.IsSpace(r):
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+			continue
+		}
+		word.WriteRune(r)
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+
+	var term string
+	flush := func() error {
+		if term == "" {
+			return errors.New("empty filter expression term")
+		}
+		terms = append(terms, term)
+		term = ""
+		return nil
+	}
+	for _, word := range words {
+		if strings.EqualFold(word, "AND") {
+			if err = flush(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if term != "" {
+			return nil, fmt.Errorf("expected AND before %q", word)
+		}
+		term = word
+	}
+	if len(terms) == 0 && term == "" {
+		return nil, nil
+	}
+	if err = flush(); err != nil {
+		return nil, err
+	}
+	return terms, nil
+}
+
+// splitValues splits the values on the commas outside quotes, removing the quotes
+func splitValues(raw string) (values []string, err error) {
+	var (
+		value  strings.Builder
+		quoted bool
+	)
+	for _, r := range raw {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			values = append(values, value.String())
+			value.Reset()
+		default:
+			value.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote")
+	}
+	return append(values, value.String()), nil
+}
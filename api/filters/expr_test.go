@@ -0,0 +1,136 @@
+package filters_test
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api/filters"
+)
+
+func TestParse(t *testing.T) {
+	type tcase struct {
+		expr     string
+		fields   filters.Fields
+		expected url.Values
+		err      bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			filter, err := filters.Parse(tc.expr, tc.fields)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("Expected an error, got %v", filter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+			values := make(url.Values)
+			if err = filter.Add(values); err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+			if !reflect.DeepEqual(values, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, values)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"comparisons": {
+			expr:   "name-contains:plan AND versionNumber-ge:3",
+			fields: filters.ItemVersionFields,
+			expected: url.Values{
+				"filter[name]-contains":    {"plan"},
+				"filter[versionNumber]-ge": {"3"},
+			},
+		},
+		"equality and values": {
+			expr:     "extension.type:a,b and hidden:false",
+			fields:   filters.FolderContentFields,
+			expected: url.Values{"filter[extension.type]": {"a", "b"}, "filter[hidden]": {"false"}},
+		},
+		"quoted": {
+			expr:     `name-starts:"floor plan, AND more"`,
+			fields:   filters.HubFields,
+			expected: url.Values{"filter[name]-starts": {"floor plan, AND more"}},
+		},
+		"quoted whitespace": {
+			expr:     "name:\"a  b\" AND\textension.type:\"c\td\"",
+			fields:   filters.HubFields,
+			expected: url.Values{"filter[name]": {"a  b"}, "filter[extension.type]": {"c\td"}},
+		},
+		"any field": {
+			expr:     "attributes.custom:x",
+			expected: url.Values{"filter[attributes.custom]": {"x"}},
+		},
+		"empty":              {expr: " ", expected: url.Values{}},
+		"unknown field":      {expr: "versionNumber:1", fields: filters.ProjectFields, err: true},
+		"unknown comparison": {expr: "name-like:a", fields: filters.HubFields, err: true},
+		"ordering a string":  {expr: "name-gt:a", fields: filters.HubFields, err: true},
+		"contains a number":  {expr: "versionNumber-contains:1", fields: filters.ItemVersionFields, err: true},
+		"not a number":       {expr: "versionNumber:one", fields: filters.ItemVersionFields, err: true},
+		"not a date":         {expr: "createTime-lt:yesterday", fields: filters.ItemVersionFields, err: true},
+		"empty name":         {expr: "-eq:a", err: true},
+		"missing value":      {expr: "name", err: true},
+		"missing AND":        {expr: "name:a id:b", err: true},
+		"dangling AND":       {expr: "name:a AND", err: true},
+		"unterminated quote": {expr: `name:"a`, err: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	filter, err := filters.For(filters.FolderContentFields).
+		Where("lastModifiedTime", filters.ComparisonGreaterOrEqual, "2023-01-02T03:04:05Z").
+		Where("type", filters.ComparisonNone, "items").
+		Build()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	values := make(url.Values)
+	if err = filters.RunAll(values, filter); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	expected := url.Values{"filter[lastModifiedTime]-ge": {"2023-01-02T03:04:05Z"}, "filter[type]": {"items"}}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+
+	_, err = filters.For(filters.HubFields).Where("", filters.ComparisonEqual, "a").Where("id", filters.ComparisonLess, "b").Build()
+	if !errors.Is(err, filters.ErrEmptyName) {
+		t.Errorf("Expected ErrEmptyName, got %v", err)
+	}
+	_, err = filters.For(filters.HubFields).Where("id", filters.ComparisonLess, "b").Build()
+	var invalid filters.ValidationError
+	if !errors.As(err, &invalid) || invalid.Filter.Name != "id" {
+		t.Errorf("Expected a ValidationError for id, got %v", err)
+	}
+}
+
+func TestFilter_EmptyName(t *testing.T) {
+	err := filters.Filter{Values: []string{"a"}}.Add(make(url.Values))
+	if !errors.Is(err, filters.ErrEmptyName) {
+		t.Errorf("Expected ErrEmptyName, got %v", err)
+	}
+}
+
+func TestFilters_Validate(t *testing.T) {
+	where := filters.Filters{
+		{Name: "name", Comparison: filters.ComparisonContains, Values: []string{"plan"}},
+		{Name: "size", Comparison: filters.ComparisonGreater, Values: []string{"3"}},
+	}
+	if err := where[:1].Validate(filters.HubFields); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	var invalid filters.ValidationError
+	if err := where.Validate(filters.HubFields); !errors.As(err, &invalid) || invalid.Filter.Name != "size" {
+		t.Errorf("Expected a ValidationError for size, got %v", err)
+	}
+}
@@ -125,6 +125,9 @@ type Filter struct {
 }
 
 func (filter Filter) Add(values url.Values) error {
+	if filter.Name == "" {
+		return ErrEmptyName
+	}
 	key := fmt.Sprintf(keyFmt, filter.Name, filter.Comparison)
 	return addStringSlice(key, filter.Values, values)
 }
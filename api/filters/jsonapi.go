@@ -0,0 +1,86 @@
+package filters
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	keyFieldsFmt     = "fields[%v]"
+	KeyInclude       = "include"
+	KeyIncludeHidden = "includeHidden"
+	KeySort          = "sort"
+)
+
+// joinNames joins the names with commas, returning ErrEmptyName if one is empty
+func joinNames(names []string) (string, error) {
+	for _, name := range names {
+		if name == "" {
+			return "", ErrEmptyName
+		}
+	}
+	return strings.Join(names, ","), nil
+}
+
+// Include are the relationships whose resources are returned in the included
+// resources of the response, g.e. Include{"tip"}
+type Include []string
+
+func (filter Include) Add(values url.Values) error {
+	if len(filter) == 0 {
+		return nil
+	}
+	include, err := joinNames(filter)
+	if err != nil {
+		return err
+	}
+	values.Set(KeyInclude, include)
+	return nil
+}
+
+// IncludeHidden returns the hidden resources along with the others
+type IncludeHidden bool
+
+func (filter IncludeHidden) Add(values url.Values) error {
+	if filter {
+		values.Set(KeyIncludeHidden, "true")
+	}
+	return nil
+}
+
+// SparseFields are the attributes returned for the resources of a type, g.e.
+//
+//	SparseFields{"items": {"displayName"}, "versions": {"versionNumber", "storageSize"}}
+type SparseFields map[string][]string
+
+func (filter SparseFields) Add(values url.Values) error {
+	for typ, fields := range filter {
+		if typ == "" {
+			return ErrEmptyName
+		}
+		names, err := joinNames(fields)
+		if err != nil {
+			return fmt.Errorf("fields of %v: %w", typ, err)
+		}
+		values.Set(fmt.Sprintf(keyFieldsFmt, typ), names)
+	}
+	return nil
+}
+
+// Sort are the fields the resources are sorted by, in order; fields prefixed
+// with a - are sorted in descending order, g.e. Sort{"-lastModifiedTime", "name"}
+type Sort []string
+
+func (filter Sort) Add(values url.Values) error {
+	if len(filter) == 0 {
+		return nil
+	}
+	for _, field := range filter {
+		if strings.TrimPrefix(field, "-") == "" {
+			return ErrEmptyName
+		}
+	}
+	values.Set(KeySort, strings.Join(filter, ","))
+	return nil
+}
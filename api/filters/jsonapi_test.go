@@ -0,0 +1,68 @@
+package filters_test
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/api/filters"
+)
+
+func TestJSONAPIParameters(t *testing.T) {
+	type tcase struct {
+		filters  []api.Filterer
+		expected url.Values
+		err      error
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			values := make(url.Values)
+			err := filters.RunAll(values, tc.filters...)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("Expected error %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+			if !reflect.DeepEqual(values, tc.expected) {
+				t.Errorf("Expected %v, got %v", tc.expected, values)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"all": {
+			filters: []api.Filterer{
+				filters.Include{"tip", "parent"},
+				filters.IncludeHidden(true),
+				filters.SparseFields{"items": {"displayName"}, "versions": {"versionNumber", "storageSize"}},
+				filters.Sort{"-lastModifiedTime", "name"},
+			},
+			expected: url.Values{
+				"include":          {"tip,parent"},
+				"includeHidden":    {"true"},
+				"fields[items]":    {"displayName"},
+				"fields[versions]": {"versionNumber,storageSize"},
+				"sort":             {"-lastModifiedTime,name"},
+			},
+		},
+		"zero values": {
+			filters:  []api.Filterer{filters.Include(nil), filters.IncludeHidden(false), filters.SparseFields(nil), filters.Sort(nil)},
+			expected: url.Values{},
+		},
+		"empty include":    {filters: []api.Filterer{filters.Include{"tip", ""}}, err: filters.ErrEmptyName},
+		"empty field type": {filters: []api.Filterer{filters.SparseFields{"": {"name"}}}, err: filters.ErrEmptyName},
+		"empty field":      {filters: []api.Filterer{filters.SparseFields{"items": {""}}}, err: filters.ErrEmptyName},
+		"empty sort field": {filters: []api.Filterer{filters.Sort{"name", "-"}}, err: filters.ErrEmptyName},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
@@ -0,0 +1,268 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+)
+
+// DefaultMaxLoggedBody is the number of bytes of the bodies that are logged
+const DefaultMaxLoggedBody = 2048
+
+// requestIDHeaders are the response headers that may carry the id of the request
+var requestIDHeaders = []string{"x-ads-request-id", "x-request-id"}
+
+// Logging logs the http traffic to Forge with log/slog. For each request, the method,
+// url, query (the filters), operation, status, latency, request id and response size are
+// logged; the bodies are logged too when the logger is enabled for BodyLevel.
+//
+// Secrets are always redacted: the Authorization header, the client_secret, refresh_token
+// and access_token fields of form and JSON bodies, and the query of signed urls.
+type Logging struct {
+	// Logger is where the records go, if nil slog.Default() is used
+	Logger *slog.Logger
+	// Level of the records of successful requests, if nil slog.LevelInfo is used.
+	// Requests failing with a status of 400 and above are logged at slog.LevelWarn,
+	// the ones that got no response at slog.LevelError.
+	Level slog.Leveler
+	// BodyLevel is the level the bodies are logged at, if nil slog.LevelDebug is used
+	BodyLevel slog.Leveler
+	// MaxBodySize is the number of bytes of each body that are logged, bodies are
+	// truncated past it. If 0 DefaultMaxLoggedBody is used.
+	MaxBodySize int
+	// BodySampleRate is the fraction, between 0 and 1, of the requests whose bodies
+	// are logged. If 0 all the bodies are logged.
+	BodySampleRate float64
+}
+
+func (l *Logging) logger() *slog.Logger {
+	if l.Logger == nil {
+		return slog.Default()
+	}
+	return l.Logger
+}
+
+func leveler(l slog.Leveler, def slog.Level) slog.Level {
+	if l == nil {
+		return def
+	}
+	return l.Level()
+}
+
+func (l *Logging) maxBodySize() int {
+	if l.MaxBodySize <= 0 {
+		return DefaultMaxLoggedBody
+	}
+	return l.MaxBodySize
+}
+
+// logBodies returns true if the bodies of the request should be logged
+func (l *Logging) logBodies(ctx context.Context) bool {
+	if !l.logger().Enabled(ctx, leveler(l.BodyLevel, slog.LevelDebug)) {
+		return false
+	}
+	return l.BodySampleRate <= 0 || l.BodySampleRate >= 1 || rand.Float64() < l.BodySampleRate
+}
+
+// Transport returns a RoundTripper logging the traffic sent through base;
+// if base is nil http.DefaultTransport is used. It can be set on the http.Client
+// of an authenticator to log the authentication requests.
+func (l *Logging) Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return loggingTransport{log: l, base: base}
+}
+
+type loggingTransport struct {
+	log  *Logging
+	base http.RoundTripper
+}
+
+func (t loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	start := time.Now()
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", RedactURL(&url.URL{Scheme: req.URL.Scheme, Host: req.URL.Host, Path: req.URL.Path})),
+	}
+	if req.URL.RawQuery != "" {
+		attrs = append(attrs, slog.String("query", redactQuery(req.URL.Query())))
+	}
+	if op, ok := OperationFrom(ctx); ok {
+		attrs = append(attrs, slog.String("operation", op.Name))
+	}
+
+	bodies := t.log.logBodies(ctx)
+	if bodies {
+		attrs = append(attrs, slog.Any("request_headers", RedactHeader(req.Header)))
+		if req.Body != nil && req.Body != http.NoBody {
+			// only the logged part of the body is read ahead, so large uploads are not buffered
+			head := make([]byte, t.log.maxBodySize()+1)
+			n, err := io.ReadFull(req.Body, head)
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				return nil, err
+			}
+			head = head[:n]
+			req = req.Clone(ctx)
+			req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), req.Body), Closer: req.Body}
+			attrs = append(attrs, slog.String("request_body", t.log.body(head, req.Header.Get("Content-Type"))))
+		}
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		attrs = append(attrs, slog.Duration("latency", time.Since(start)), slog.String("error", err.Error()))
+		t.log.logger().LogAttrs(ctx, slog.LevelError, "forge request failed", attrs...)
+		return nil, err
+	}
+
+	attrs = append(attrs, slog.Int("status", res.StatusCode), slog.Duration("latency", time.Since(start)))
+	for _, name := range requestIDHeaders {
+		if id := res.Header.Get(name); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+			break
+		}
+	}
+	body := &loggingBody{ReadCloser: res.Body, log: t.log, ctx: ctx, attrs: attrs, status: res.StatusCode, length: res.ContentLength}
+	if bodies {
+		body.capture = new(bytes.Buffer)
+		body.contentType = res.Header.Get("Content-Type")
+	}
+	res.Body = body
+	return res, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// loggingBody logs the request once the response body is closed, so the size of
+// the response is known
+type loggingBody struct {
+	io.ReadCloser
+	log         *Logging
+	ctx         context.Context
+	attrs       []slog.Attr
+	status      int
+	length      int64 // Content-Length, -1 if unknown
+	size        int64 // bytes read
+	capture     *bytes.Buffer
+	contentType string
+	once        sync.Once
+}
+
+func (b *loggingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+	if b.capture != nil && b.capture.Len() <= b.log.maxBodySize() {
+		b.capture.Write(p[:n])
+	}
+	return n, err
+}
+
+func (b *loggingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		size := b.size
+		if size == 0 && b.length > 0 {
+			// the body was not read
+			size = b.length
+		}
+		attrs := append(b.attrs, slog.Int64("response_size", size))
+		if b.capture != nil {
+			attrs = append(attrs, slog.String("response_body", b.log.body(b.capture.Bytes(), b.contentType)))
+		}
+		level := leveler(b.log.Level, slog.LevelInfo)
+		if b.status >= http.StatusBadRequest {
+			level = slog.LevelWarn
+		}
+		b.log.logger().LogAttrs(b.ctx, level, "forge request", attrs...)
+	})
+	return err
+}
+
+// body returns the redacted, truncated body
+func (l *Logging) body(data []byte, contentType string) string {
+	max := l.maxBodySize()
+	truncated := len(data) > max
+	if truncated {
+		data = data[:max]
+	}
+	text := RedactBody(string(data), contentType)
+	if truncated {
+		text += "...(truncated)"
+	}
+	return text
+}
+
+// secretFields are the body fields and query parameters that are always redacted
+var secretFields = []string{"client_secret", "refresh_token", "access_token", "code", "password", "id_token"}
+
+// signatureParams are the query parameters marking a signed url
+var signatureParams = []string{"signature", "x-amz-signature", "sig", "token", "policy", "key-pair-id"}
+
+var (
+	jsonSecrets = regexp.MustCompile(`("(?:client_secret|refresh_token|access_token|id_token|password)"\s*:\s*")[^"]*`)
+	formSecrets = regexp.MustCompile(`((?:^|&)(?:client_secret|refresh_token|access_token|code|password|id_token)=)[^&]*`)
+	signedURLs  = regexp.MustCompile(`(?i)(https?://[^\s"?]+)\?[^\s"]*(?:signature|sig|token|policy|key-pair-id)=[^\s"]*`)
+)
+
+// RedactBody returns the body with its secrets and signed urls redacted.
+// The body may be truncated.
+func RedactBody(body, contentType string) string {
+	if strings.HasPrefix(contentType, ContentTypeFormEncoded) {
+		body = formSecrets.ReplaceAllString(body, "${1}"+oauth.Redacted)
+	} else {
+		body = jsonSecrets.ReplaceAllString(body, "${1}"+oauth.Redacted)
+	}
+	return signedURLs.ReplaceAllString(body, "${1}?"+oauth.Redacted)
+}
+
+// RedactHeader returns a copy of header with the Authorization header redacted
+func RedactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	if header.Get("Authorization") != "" {
+		header.Set("Authorization", oauth.Redacted)
+	}
+	return header
+}
+
+// RedactURL returns the url with the query of signed urls, and the secret parameters, redacted
+func RedactURL(u *url.URL) string {
+	redacted := *u
+	redacted.User = nil
+	if redacted.RawQuery != "" {
+		redacted.RawQuery = redactQuery(u.Query())
+	}
+	return redacted.String()
+}
+
+func redactQuery(query url.Values) string {
+	for key := range query {
+		for _, param := range signatureParams {
+			if strings.EqualFold(key, param) {
+				return oauth.Redacted
+			}
+		}
+	}
+	for key := range query {
+		for _, field := range secretFields {
+			if strings.EqualFold(key, field) {
+				query.Set(key, oauth.Redacted)
+			}
+		}
+	}
+	return query.Encode()
+}
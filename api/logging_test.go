@@ -0,0 +1,123 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+	"github.com/gdey/forge-api-go-client/oauth/twolegged"
+)
+
+// logRecords decodes the JSON log records
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	decoder := json.NewDecoder(buf)
+	for decoder.More() {
+		record := make(map[string]interface{})
+		if err := decoder.Decode(&record); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ads-request-id", "request-1")
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/authentication/v1/authenticate" {
+			w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"secret-access-token"}`))
+			return
+		}
+		w.Write([]byte(`{"signedUrl":"https://bucket.example.com/object?Signature=secret-signature&Expires=1"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logging := &api.Logging{
+		Logger:      slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		MaxBodySize: 64,
+	}
+
+	auth := static.New("secret-bearer")
+	auth.Host = server.URL
+	client := api.NewClient(auth)
+	client.Log = logging
+	filter := filterFunc(func(values url.Values) error {
+		values.Set("limit", "10")
+		return nil
+	})
+	var result map[string]string
+	if err := client.Get(context.Background(), scopes.BucketRead, []string{"oss", "v2", "buckets"}, &result, filter); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+
+	twoLegged := twolegged.NewAuth("the-client", "secret-client-secret")
+	twoLegged.Host = server.URL
+	twoLegged.Log = logging
+	if _, err := twoLegged.Authenticate(scopes.DataRead); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+
+	logged := buf.String()
+	for _, secret := range []string{"secret-bearer", "secret-signature", "secret-client-secret", "secret-access-token"} {
+		if strings.Contains(logged, secret) {
+			t.Errorf("Expected %v to be redacted, got %s", secret, logged)
+		}
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 records, got %d", len(records))
+	}
+	record := records[0]
+	if record["method"] != http.MethodGet || record["query"] != "limit=10" || record["status"] != float64(http.StatusOK) ||
+		record["request_id"] != "request-1" || record["response_size"] == float64(0) {
+		t.Errorf("Unexpected record: %v", record)
+	}
+	if body, _ := record["response_body"].(string); !strings.HasSuffix(body, "...(truncated)") {
+		t.Errorf("Expected the response body to be truncated, got %q", body)
+	}
+	if body, _ := records[1]["request_body"].(string); !strings.Contains(body, "client_secret=REDACTED") {
+		t.Errorf("Expected the client secret to be redacted from the form, got %q", body)
+	}
+}
+
+func TestLogging_Level(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	auth := static.New("token")
+	auth.Host = server.URL
+	client := api.NewClient(auth)
+	// bodies are logged at debug, so are left out
+	client.Log = &api.Logging{Logger: slog.New(slog.NewJSONHandler(&buf, nil))}
+	if err := client.Get(context.Background(), scopes.BucketRead, []string{"oss", "v2", "buckets"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	records := logRecords(t, &buf)
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 record, got %d", len(records))
+	}
+	if _, ok := records[0]["response_body"]; ok {
+		t.Errorf("Expected no body to be logged, got %v", records[0])
+	}
+}
+
+type filterFunc func(url.Values) error
+
+func (fn filterFunc) Add(values url.Values) error { return fn(values) }
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// The metrics recorded by a Client
+const (
+	// MetricRequests counts the requests, by operation and class (see ErrorClass)
+	MetricRequests = "forge_requests_total"
+	// MetricRequestDuration is the histogram of the latency, in seconds, of the requests by operation
+	MetricRequestDuration = "forge_request_duration_seconds"
+	// MetricRetries counts the retries of rate limited requests, by operation
+	MetricRetries = "forge_request_retries_total"
+)
+
+// UnknownOperation is the operation name used for requests made without an Operation in their context
+const UnknownOperation = "unknown"
+
+// The error classes, see ErrorClass
+const (
+	ClassOK           = "ok"
+	ClassRateLimited  = "rate_limited"
+	ClassSystemIssue  = "system_issue"
+	ClassTokenExpired = "token_expired"
+	ClassUnauthorized = "unauthorized"
+	ClassForbidden    = "forbidden"
+	ClassNotFound     = "not_found"
+	ClassClientError  = "client_error"
+	ClassNetwork      = "network"
+	ClassCanceled     = "canceled"
+)
+
+// Labels are the dimensions of a measurement, g.e. {"operation": "oss.objects.upload"}
+type Labels map[string]string
+
+// Metrics receives the measurements of the requests made by a Client. Implementations
+// must be safe for concurrent use.
+type Metrics interface {
+	// Add adds delta to the named counter
+	Add(name string, labels Labels, delta float64)
+	// Observe records value in the named histogram
+	Observe(name string, labels Labels, value float64)
+}
+
+// ErrorClass returns the class of the error of a request, used to label metrics:
+// ClassOK for no error, the class matching the ErrResult checks (g.e. IsRateLimited)
+// for an ErrResult, and ClassCanceled or ClassNetwork for the other errors.
+func ErrorClass(err error) string {
+	if err == nil {
+		return ClassOK
+	}
+	var errResult ErrResult
+	if !errors.As(err, &errResult) {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return ClassCanceled
+		}
+		return ClassNetwork
+	}
+	switch {
+	case errResult.IsRateLimited():
+		return ClassRateLimited
+	case errResult.IsSystemIssue():
+		return ClassSystemIssue
+	case errResult.IsTokenExpired():
+		return ClassTokenExpired
+	case errResult.IsUnauthorized():
+		return ClassUnauthorized
+	case errResult.IsForbidden():
+		return ClassForbidden
+	case errResult.IsNotFound():
+		return ClassNotFound
+	default:
+		return ClassClientError
+	}
+}
+
+// operationName returns the name of the operation of ctx, or UnknownOperation
+func operationName(ctx context.Context) string {
+	if op, ok := OperationFrom(ctx); ok {
+		return op.Name
+	}
+	return UnknownOperation
+}
+
+// observeRequest records the metrics of a request that got res or failed with err
+func (c *Client) observeRequest(ctx context.Context, start time.Time, res *http.Response, err error) {
+	if c == nil || c.Metrics == nil {
+		return
+	}
+	if err == nil && res.StatusCode >= http.StatusBadRequest {
+		err = ErrResult{StatusCode: res.StatusCode}
+	}
+	operation := operationName(ctx)
+	c.Metrics.Add(MetricRequests, Labels{"operation": operation, "class": ErrorClass(err)}, 1)
+	c.Metrics.Observe(MetricRequestDuration, Labels{"operation": operation}, time.Since(start).Seconds())
+}
+
+// observeRetry records the retry of a rate limited request
+func (c *Client) observeRetry(ctx context.Context) {
+	if c == nil || c.Metrics == nil {
+		return
+	}
+	c.Metrics.Add(MetricRetries, Labels{"operation": operationName(ctx)}, 1)
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// Operation describes a single Forge API call and the scopes it needs
+type Operation struct {
+	// Name is the normalized name of the operation g.e. oss.objects.upload
+	Name string
+	// Method is the http method used by the operation
+	Method string
+	// Scope is the narrowest set of scopes the operation needs
+	Scope scopes.Scope
+	// Idempotent marks a POST operation as safe to send again, see IsIdempotent
+	Idempotent bool
+}
+
+func (op Operation) String() string { return op.Name }
+
+// IsIdempotent returns true if the operation can be sent again, g.e. after it was rate
+// limited: GET, HEAD, OPTIONS, PUT and DELETE operations always are, the others only if
+// they are marked Idempotent.
+func (op Operation) IsIdempotent() bool {
+	return op.Idempotent || idempotentMethod(op.Method)
+}
+
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// Context returns a copy of ctx that carries the operation
+func (op Operation) Context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+type operationKey struct{}
+
+// OperationFrom returns the operation carried by the ctx
+func OperationFrom(ctx context.Context) (op Operation, ok bool) {
+	op, ok = ctx.Value(operationKey{}).(Operation)
+	return op, ok
+}
+
+var operations = struct {
+	sync.RWMutex
+	byName map[string]Operation
+}{byName: make(map[string]Operation)}
+
+// RegisterOperation adds the operation to the registry and returns it. It is meant
+// to be used to declare package level variables; it will panic if the operation
+// is already registered or does not have a valid scope.
+func RegisterOperation(op Operation) Operation {
+	if op.Name == "" || op.Method == "" {
+		panic("operation name and method are required")
+	}
+	if !op.Scope.IsValid() {
+		panic(fmt.Sprintf("operation %v: invalid scope %#x", op.Name, uint64(op.Scope)))
+	}
+	operations.Lock()
+	defer operations.Unlock()
+	if _, ok := operations.byName[op.Name]; ok {
+		panic(fmt.Sprintf("operation %v already registered", op.Name))
+	}
+	operations.byName[op.Name] = op
+	return op
+}
+
+// LookupOperation returns the registered operation with the given name
+func LookupOperation(name string) (op Operation, ok bool) {
+	operations.RLock()
+	defer operations.RUnlock()
+	op, ok = operations.byName[name]
+	return op, ok
+}
+
+// Operations returns all the registered operations sorted by name
+func Operations() []Operation {
+	operations.RLock()
+	ops := make([]Operation, 0, len(operations.byName))
+	for _, op := range operations.byName {
+		ops = append(ops, op)
+	}
+	operations.RUnlock()
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Name < ops[j].Name })
+	return ops
+}
+
+// ScopesFor returns the union of the scopes needed by the given operations. This is
+// useful for knowing what scopes to request from the end user in a 3-legged flow.
+func ScopesFor(ops ...Operation) (scope scopes.Scope) {
+	for _, op := range ops {
+		scope |= op.Scope
+	}
+	return scope
+}
+
+// ScopesForNames is like ScopesFor but takes the names of registered operations
+func ScopesForNames(names ...string) (scope scopes.Scope, err error) {
+	for _, name := range names {
+		op, ok := LookupOperation(name)
+		if !ok {
+			return scope, fmt.Errorf("unknown operation %q", name)
+		}
+		scope |= op.Scope
+	}
+	return scope, nil
+}
@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+func TestOperations(t *testing.T) {
+	opRead := api.RegisterOperation(api.Operation{Name: "test.things.get", Method: http.MethodGet, Scope: scopes.DataRead})
+	opCreate := api.RegisterOperation(api.Operation{Name: "test.buckets.create", Method: http.MethodPost, Scope: scopes.BucketCreate})
+	t.Cleanup(func() {
+		api.UnregisterOperation(opRead.Name)
+		api.UnregisterOperation(opCreate.Name)
+	})
+
+	t.Run("Lookup", func(t *testing.T) {
+		op, ok := api.LookupOperation("test.things.get")
+		if !ok || op != opRead {
+			t.Errorf("Expected to find %v, got %v", opRead, op)
+		}
+	})
+
+	t.Run("Scopes for a workflow", func(t *testing.T) {
+		if got := api.ScopesFor(opRead, opCreate); got != scopes.DataRead|scopes.BucketCreate {
+			t.Errorf("Unexpected scopes: %q", got)
+		}
+		got, err := api.ScopesForNames("test.things.get", "test.buckets.create")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if got != scopes.DataRead|scopes.BucketCreate {
+			t.Errorf("Unexpected scopes: %q", got)
+		}
+		if _, err = api.ScopesForNames("test.nothing"); err == nil {
+			t.Errorf("Expected an error for an unknown operation")
+		}
+	})
+
+	t.Run("Duplicate registration", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("Expected a panic for a duplicate operation")
+			}
+		}()
+		api.RegisterOperation(opRead)
+	})
+
+	t.Run("Operation in context", func(t *testing.T) {
+		op, ok := api.OperationFrom(opCreate.Context(context.Background()))
+		if !ok || op != opCreate {
+			t.Errorf("Expected %v in context, got %v", opCreate, op)
+		}
+	})
+
+	t.Run("Registered operations have valid scopes", func(t *testing.T) {
+		for _, op := range api.Operations() {
+			if !op.Scope.IsValid() {
+				t.Errorf("%v has an invalid scope", op)
+			}
+		}
+	})
+}
@@ -0,0 +1,222 @@
+// Package prometheus provides an api.Metrics that serves the measurements in the
+// Prometheus text exposition format, so they can be scraped without pulling in the
+// Prometheus client library. g.e.
+//
+//	registry := prometheus.NewRegistry()
+//	client.Metrics = registry
+//	http.Handle("/metrics", registry)
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gdey/forge-api-go-client/api"
+)
+
+// ContentType is the content type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are the upper bounds, in seconds, of the histogram buckets
+var DefaultBuckets = []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// help is the description of the metrics recorded by api.Client
+var help = map[string]string{
+	api.MetricRequests:        "Forge requests by operation and error class.",
+	api.MetricRequestDuration: "Latency of the Forge requests, in seconds, by operation.",
+	api.MetricRetries:         "Retries of rate limited Forge requests, by operation.",
+}
+
+type series struct {
+	labels string // the encoded labels, g.e. {operation="oss.buckets.list"}
+	value  float64
+	// histograms only
+	counts []uint64
+	count  uint64
+}
+
+type family struct {
+	histogram bool
+	series    map[string]*series
+}
+
+// Registry keeps the counters and histograms in memory, it is safe for concurrent use
+type Registry struct {
+	// Buckets of the histograms, if nil DefaultBuckets are used. It must not change
+	// once values have been observed.
+	Buckets []float64
+
+	mutex    sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry returns an empty registry using the DefaultBuckets
+func NewRegistry() *Registry {
+	return &Registry{Buckets: DefaultBuckets}
+}
+
+func (r *Registry) buckets() []float64 {
+	if r.Buckets == nil {
+		return DefaultBuckets
+	}
+	return r.Buckets
+}
+
+// series returns the series of the metric for the labels; the mutex must be held
+func (r *Registry) series(name string, labels api.Labels, histogram bool) *series {
+	if r.families == nil {
+		r.families = make(map[string]*family)
+	}
+	fam, ok := r.families[name]
+	if !ok {
+		fam = &family{histogram: histogram, series: make(map[string]*series)}
+		r.families[name] = fam
+	}
+	key := encodeLabels(labels)
+	s, ok := fam.series[key]
+	if !ok {
+		s = &series{labels: key}
+		if histogram {
+			s.counts = make([]uint64, len(r.buckets()))
+		}
+		fam.series[key] = s
+	}
+	return s
+}
+
+// Add adds delta to the named counter
+func (r *Registry) Add(name string, labels api.Labels, delta float64) {
+	r.mutex.Lock()
+	r.series(name, labels, false).value += delta
+	r.mutex.Unlock()
+}
+
+// Observe records value in the named histogram
+func (r *Registry) Observe(name string, labels api.Labels, value float64) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	s := r.series(name, labels, true)
+	s.value += value
+	s.count++
+	for i, bound := range r.buckets() {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+}
+
+// WriteTo writes all the metrics in the text exposition format, sorted by name and labels
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cw := &countingWriter{Writer: bufio.NewWriter(w)}
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fam := r.families[name]
+		if text, ok := help[name]; ok {
+			fmt.Fprintf(cw, "# HELP %s %s\n", name, text)
+		}
+		kind := "counter"
+		if fam.histogram {
+			kind = "histogram"
+		}
+		fmt.Fprintf(cw, "# TYPE %s %s\n", name, kind)
+
+		keys := make([]string, 0, len(fam.series))
+		for key := range fam.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			s := fam.series[key]
+			if !fam.histogram {
+				fmt.Fprintf(cw, "%s%s %s\n", name, s.labels, formatFloat(s.value))
+				continue
+			}
+			for i, bound := range r.buckets() {
+				fmt.Fprintf(cw, "%s_bucket%s %d\n", name, withLabel(s.labels, "le", formatFloat(bound)), s.counts[i])
+			}
+			fmt.Fprintf(cw, "%s_bucket%s %d\n", name, withLabel(s.labels, "le", "+Inf"), s.count)
+			fmt.Fprintf(cw, "%s_sum%s %s\n", name, s.labels, formatFloat(s.value))
+			fmt.Fprintf(cw, "%s_count%s %d\n", name, s.labels, s.count)
+		}
+	}
+	if err := cw.Writer.(*bufio.Writer).Flush(); err != nil {
+		return cw.n, err
+	}
+	return cw.n, cw.err
+}
+
+// ServeHTTP serves the metrics, so the registry can be scraped
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	r.WriteTo(w)
+}
+
+type countingWriter struct {
+	io.Writer
+	n   int64
+	err error
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.Writer.Write(p)
+	w.n += int64(n)
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	return n, err
+}
+
+// encodeLabels returns the labels sorted by name, g.e. {class="ok",operation="x"}
+func encodeLabels(labels api.Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + escape(labels[name]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// withLabel adds a label to the encoded labels
+func withLabel(encoded, name, value string) string {
+	pair := name + `="` + escape(value) + `"`
+	if encoded == "" {
+		return "{" + pair + "}"
+	}
+	return encoded[:len(encoded)-1] + "," + pair + "}"
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escape(value string) string { return escaper.Replace(value) }
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
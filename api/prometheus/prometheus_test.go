@@ -0,0 +1,91 @@
+package prometheus_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/api/prometheus"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+func TestRegistry(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch {
+		case requests == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/oss/v2/buckets/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	registry := prometheus.NewRegistry()
+	registry.Buckets = []float64{1, 60}
+	auth := static.New("token")
+	auth.Host = server.URL
+	client := api.NewClient(auth)
+	client.Metrics = registry
+
+	ctx := api.Operation{Name: "oss.buckets.list"}.Context(context.Background())
+	if err := client.Get(ctx, 0, []string{"oss", "v2", "buckets"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if err := client.Get(context.Background(), 0, []string{"oss", "v2", "buckets", "missing"}, nil); err == nil {
+		t.Fatalf("Expected an error for the missing bucket")
+	}
+
+	metrics := httptest.NewServer(registry)
+	defer metrics.Close()
+	res, err := http.Get(metrics.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	defer res.Body.Close()
+	if got := res.Header.Get("Content-Type"); got != prometheus.ContentType {
+		t.Errorf("Expected content type %v, got %v", prometheus.ContentType, got)
+	}
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	exposition := string(body)
+
+	for _, line := range []string{
+		"# TYPE forge_requests_total counter",
+		`forge_requests_total{class="not_found",operation="unknown"} 1`,
+		`forge_requests_total{class="ok",operation="oss.buckets.list"} 1`,
+		`forge_requests_total{class="rate_limited",operation="oss.buckets.list"} 1`,
+		`forge_request_retries_total{operation="oss.buckets.list"} 1`,
+		"# TYPE forge_request_duration_seconds histogram",
+		`forge_request_duration_seconds_bucket{operation="oss.buckets.list",le="60"} 2`,
+		`forge_request_duration_seconds_bucket{operation="oss.buckets.list",le="+Inf"} 2`,
+		`forge_request_duration_seconds_count{operation="oss.buckets.list"} 2`,
+	} {
+		if !strings.Contains(exposition, line+"\n") {
+			t.Errorf("Expected the line %q in:\n%s", line, exposition)
+		}
+	}
+
+	t.Run("Escaping", func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		registry.Add("custom_total", api.Labels{"name": "a \"quoted\"\\name\n"}, 2.5)
+		var buf strings.Builder
+		if _, err := registry.WriteTo(&buf); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		expected := "# TYPE custom_total counter\ncustom_total{name=\"a \\\"quoted\\\"\\\\name\\n\"} 2.5\n"
+		if buf.String() != expected {
+			t.Errorf("Expected %q, got %q", expected, buf.String())
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package api
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Limit is the rate of a token bucket
+type Limit struct {
+	// Rate is the number of requests per second, g.e. 50.0/60 for 50 requests per minute
+	Rate float64
+	// Burst is the number of requests that can be sent at once, at least 1
+	Burst int
+}
+
+// DefaultFamily is the family of the requests made without an Operation in their context
+const DefaultFamily = "default"
+
+// DefaultLimits are approximations of the rate limits Forge documents for each family
+var DefaultLimits = map[string]Limit{
+	"oss.uploads": {Rate: 500.0 / 60, Burst: 10},
+	"oss.reads":   {Rate: 1000.0 / 60, Burst: 20},
+	"oss.writes":  {Rate: 300.0 / 60, Burst: 5},
+	"md.jobs":     {Rate: 50.0 / 60, Burst: 5},
+	"md.reads":    {Rate: 300.0 / 60, Burst: 10},
+	"dm.reads":    {Rate: 300.0 / 60, Burst: 10},
+	"recap.jobs":  {Rate: 50.0 / 60, Burst: 5},
+}
+
+// DefaultLimit is the limit of the families without a limit
+var DefaultLimit = Limit{Rate: 100.0 / 60, Burst: 10}
+
+// Family returns the rate limit family of the operation, from its name and method:
+// g.e. oss.uploads for oss.objects.upload, md.jobs for md.jobs.translate and dm.reads
+// for dm.items.get. ReCap photoscenes are jobs.
+func Family(op Operation) string {
+	parts := strings.Split(op.Name, ".")
+	if op.Name == "" || len(parts) < 2 {
+		return DefaultFamily
+	}
+	service, resource, action := parts[0], parts[1], parts[len(parts)-1]
+	switch {
+	case action == "upload":
+		return service + ".uploads"
+	case resource == "jobs" || (service == "recap" && resource == "photoscene" && op.Method != http.MethodGet):
+		return service + ".jobs"
+	case op.Method == http.MethodGet || op.Method == http.MethodHead:
+		return service + ".reads"
+	default:
+		return service + ".writes"
+	}
+}
+
+// LimitStatus is the current state of the limiter of a family
+type LimitStatus struct {
+	Family string
+	// Limit is the configured limit
+	Limit Limit
+	// Rate is the current rate, lower than the configured one after requests were rate limited
+	Rate float64
+	// Tokens available; negative if requests are waiting
+	Tokens float64
+	// Throttled is the number of rate limited (429) responses
+	Throttled int
+	// PausedUntil is when requests can be sent again, after a Retry-After
+	PausedUntil time.Time
+}
+
+// RateLimiter throttles the requests, with a token bucket per family (see Family), before
+// Forge rate limits them. When a request is rate limited anyway, the rate of its family is
+// halved and its requests paused for the Retry-After of the response; the rate then
+// recovers with each successful request.
+//
+// A RateLimiter is safe for concurrent use; set the same one on the clients that share
+// the rate limits of an app.
+type RateLimiter struct {
+	// Limits by family, if nil DefaultLimits are used
+	Limits map[string]Limit
+	// Default is the limit of the families not in Limits, if zero DefaultLimit is used
+	Default Limit
+	// MinRate is the lowest rate the limiter slows down to, if 0 it is a tenth of the limit
+	MinRate float64
+
+	mutex   sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewRateLimiter returns a limiter using the DefaultLimits
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{Limits: DefaultLimits, Default: DefaultLimit}
+}
+
+type bucket struct {
+	limit       Limit
+	rate        float64
+	tokens      float64
+	last        time.Time
+	pausedUntil time.Time
+	throttled   int
+}
+
+// bucket returns the bucket of the family, refilled up to now; the mutex must be held
+func (l *RateLimiter) bucket(family string, now time.Time) *bucket {
+	if l.buckets == nil {
+		l.buckets = make(map[string]*bucket)
+	}
+	b, ok := l.buckets[family]
+	if !ok {
+		limits := l.Limits
+		if limits == nil {
+			limits = DefaultLimits
+		}
+		limit, ok := limits[family]
+		if !ok {
+			limit = l.Default
+			if limit.Rate <= 0 {
+				limit = DefaultLimit
+			}
+		}
+		if limit.Burst < 1 {
+			limit.Burst = 1
+		}
+		b = &bucket{limit: limit, rate: limit.Rate, tokens: float64(limit.Burst), last: now}
+		l.buckets[family] = b
+	}
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	return b
+}
+
+// Wait blocks until a request of the family can be sent, or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context, family string) error {
+	now := time.Now()
+	l.mutex.Lock()
+	b := l.bucket(family, now)
+	b.tokens--
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	if paused := b.pausedUntil.Sub(now); paused > wait {
+		wait = paused
+	}
+	l.mutex.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	if err := sleep(ctx, wait); err != nil {
+		// give the token back, the request is not sent
+		l.mutex.Lock()
+		b.tokens++
+		l.mutex.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Observe adjusts the rate of the family from the response to one of its requests:
+// a 429 slows it down, and pauses it for the Retry-After of the response; any other
+// response speeds it up towards its limit.
+func (l *RateLimiter) Observe(family string, res *http.Response) {
+	if res == nil {
+		return
+	}
+	now := time.Now()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	b := l.bucket(family, now)
+	if res.StatusCode != http.StatusTooManyRequests {
+		b.rate = math.Min(b.limit.Rate, b.rate+b.limit.Rate/20)
+		return
+	}
+	b.throttled++
+	minRate := l.MinRate
+	if minRate <= 0 {
+		minRate = b.limit.Rate / 10
+	}
+	b.rate = math.Max(minRate, b.rate/2)
+	b.tokens = math.Min(b.tokens, 0)
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		if until := now.Add(time.Duration(seconds) * time.Second); until.After(b.pausedUntil) {
+			b.pausedUntil = until
+		}
+	}
+}
+
+// Status returns the current state of the families that sent requests, sorted by family
+func (l *RateLimiter) Status() []LimitStatus {
+	now := time.Now()
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	status := make([]LimitStatus, 0, len(l.buckets))
+	for family := range l.buckets {
+		b := l.bucket(family, now)
+		status = append(status, LimitStatus{
+			Family:      family,
+			Limit:       b.limit,
+			Rate:        b.rate,
+			Tokens:      b.tokens,
+			Throttled:   b.throttled,
+			PausedUntil: b.pausedUntil,
+		})
+	}
+	sort.Slice(status, func(i, j int) bool { return status[i].Family < status[j].Family })
+	return status
+}
+
+// family returns the rate limit family of the operation of ctx
+func family(ctx context.Context) string {
+	op, _ := OperationFrom(ctx)
+	return Family(op)
+}
@@ -0,0 +1,132 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+func TestFamily(t *testing.T) {
+	tests := map[string]api.Operation{
+		"oss.uploads":     {Name: "oss.objects.upload", Method: http.MethodPut},
+		"oss.reads":       {Name: "oss.buckets.list", Method: http.MethodGet},
+		"oss.writes":      {Name: "oss.buckets.create", Method: http.MethodPost},
+		"md.jobs":         {Name: "md.jobs.translate", Method: http.MethodPost},
+		"dm.reads":        {Name: "dm.items.get", Method: http.MethodGet},
+		"recap.jobs":      {Name: "recap.photoscene.process", Method: http.MethodPost},
+		"recap.reads":     {Name: "recap.photoscene.progress", Method: http.MethodGet},
+		api.DefaultFamily: {},
+	}
+	for family, op := range tests {
+		t.Run(family, func(t *testing.T) {
+			if got := api.Family(op); got != family {
+				t.Errorf("Expected family %v for %v, got %v", family, op.Name, got)
+			}
+		})
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	ctx := api.Operation{Name: "oss.buckets.list", Method: http.MethodGet}.Context(context.Background())
+	paths := []string{"oss", "v2", "buckets"}
+	newClient := func(t *testing.T, limited int, requests *int) (*api.Client, *api.RateLimiter) {
+		auth := static.New("token")
+		auth.Host = newRateLimitedServer(t, limited, requests).URL
+		client := api.NewClient(auth)
+		client.RateLimit = &api.RateLimiter{Limits: map[string]api.Limit{"oss.reads": {Rate: 20, Burst: 1}}}
+		return client, client.RateLimit
+	}
+
+	t.Run("Throttle", func(t *testing.T) {
+		var requests int
+		client, _ := newClient(t, 0, &requests)
+		start := time.Now()
+		for i := 0; i < 4; i++ {
+			if err := client.Get(ctx, 0, paths, nil); err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+		}
+		// the first request uses the burst, the next ones wait 50ms each
+		if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+			t.Errorf("Expected the requests to be throttled, they took %v", elapsed)
+		}
+	})
+
+	t.Run("Rate limited", func(t *testing.T) {
+		var requests int
+		client, limiter := newClient(t, 1, &requests)
+		if err := client.Get(ctx, 0, paths, nil); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		status := limiter.Status()
+		if len(status) != 1 || status[0].Family != "oss.reads" {
+			t.Fatalf("Expected the status of oss.reads, got %v", status)
+		}
+		if status[0].Throttled != 1 {
+			t.Errorf("Expected 1 throttled request, got %d", status[0].Throttled)
+		}
+		if status[0].Rate >= status[0].Limit.Rate {
+			t.Errorf("Expected the rate to be lowered below %v, got %v", status[0].Limit.Rate, status[0].Rate)
+		}
+	})
+
+	t.Run("Retry", func(t *testing.T) {
+		type tcase struct {
+			retryAfter string
+			min, max   time.Duration
+		}
+		fn := func(tc tcase) func(*testing.T) {
+			return func(t *testing.T) {
+				var requests int
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if requests++; requests == 1 {
+						w.Header().Set("Retry-After", tc.retryAfter)
+						w.WriteHeader(http.StatusTooManyRequests)
+						return
+					}
+					w.Write([]byte(`{}`))
+				}))
+				defer server.Close()
+				auth := static.New("token")
+				auth.Host = server.URL
+				client := api.NewClient(auth)
+				client.Retry = &api.RetryPolicy{Wait: time.Second}
+				client.RateLimit = &api.RateLimiter{Limits: map[string]api.Limit{"oss.reads": {Rate: 20, Burst: 1}}}
+				start := time.Now()
+				if err := client.Get(ctx, 0, paths, nil); err != nil {
+					t.Fatalf("Unexpected error: %s\n", err.Error())
+				}
+				if elapsed := time.Since(start); elapsed < tc.min || elapsed > tc.max {
+					t.Errorf("Expected the retry to wait between %v and %v, it took %v", tc.min, tc.max, elapsed)
+				}
+			}
+		}
+		tests := map[string]tcase{
+			// the limiter pauses for the Retry-After, the retry policy does not wait again
+			"Retry-After": {retryAfter: "1", min: time.Second, max: 1800 * time.Millisecond},
+			// the limiter paces the retry at its lowered rate, instead of the policy wait
+			"No Retry-After": {retryAfter: "", min: 0, max: 500 * time.Millisecond},
+		}
+		for name, tc := range tests {
+			t.Run(name, fn(tc))
+		}
+	})
+
+	t.Run("Context done", func(t *testing.T) {
+		limiter := &api.RateLimiter{Default: api.Limit{Rate: 0.1, Burst: 1}}
+		if err := limiter.Wait(context.Background(), "slow"); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if err := limiter.Wait(ctx, "slow"); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the wait to stop with the context, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,327 @@
+// Package recorder provides an http.RoundTripper that records the Forge traffic to
+// cassette files, and replays it offline, so tests are deterministic. g.e.
+//
+//	rec, err := recorder.New("testdata/cassettes/buckets.json", recorder.ModeReplay)
+//	client.Client.Transport = rec
+//	defer rec.Stop()
+//
+// The tokens, secrets and signed urls are scrubbed before the interactions are written.
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth"
+)
+
+// Mode is what the recorder does with the requests
+type Mode int
+
+const (
+	// ModeReplay serves the requests from the cassette, without network access
+	ModeReplay Mode = iota
+	// ModeRecord sends the requests and records them in the cassette
+	ModeRecord
+)
+
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	default:
+		return fmt.Sprintf("Mode(%d)", int(m))
+	}
+}
+
+// ErrNoInteraction is returned, in replay mode, for requests not found in the cassette
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+// Body is an http body; bodies that are not valid UTF-8 are base64 encoded
+type Body []byte
+
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*b = Body(text)
+		return nil
+	}
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	*b = decoded
+	return err
+}
+
+// Request is a recorded request
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Interaction is a request with its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Cassette is the content of a cassette file
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+	// Values are the recorded values a test depends on, g.e. the environment variables
+	// naming the test resources
+	Values map[string]string `json:"values,omitempty"`
+}
+
+// Load reads the cassette file at path
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cassette Cassette
+	if err = json.Unmarshal(data, &cassette); err != nil {
+		return nil, fmt.Errorf("cassette %v: %w", path, err)
+	}
+	return &cassette, nil
+}
+
+// Save writes the cassette file at path, creating its directory if needed
+func (c *Cassette) Save(path string) error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Matcher returns true if the request matches the recorded one. Both requests are scrubbed.
+type Matcher func(req, recorded Request) bool
+
+// MatchMethod matches the http methods
+func MatchMethod(req, recorded Request) bool { return req.Method == recorded.Method }
+
+// MatchPath matches the paths of the urls, the hosts are ignored
+func MatchPath(req, recorded Request) bool {
+	return urlPart(req.URL, (*url.URL).EscapedPath) == urlPart(recorded.URL, (*url.URL).EscapedPath)
+}
+
+// MatchQuery matches the query parameters, in any order
+func MatchQuery(req, recorded Request) bool {
+	query := func(u *url.URL) string { return u.Query().Encode() }
+	return urlPart(req.URL, query) == urlPart(recorded.URL, query)
+}
+
+// MatchBody matches the bodies
+func MatchBody(req, recorded Request) bool { return bytes.Equal(req.Body, recorded.Body) }
+
+// DefaultMatchers match the method, path and query
+var DefaultMatchers = []Matcher{MatchMethod, MatchPath, MatchQuery}
+
+func urlPart(raw string, part func(*url.URL) string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return part(u)
+}
+
+// Recorder records or replays the requests sent through it
+type Recorder struct {
+	// Path of the cassette file
+	Path string
+	Mode Mode
+	// Transport sends the requests in record mode, if nil http.DefaultTransport is used
+	Transport http.RoundTripper
+	// Matchers select the recorded interaction of a request in replay mode, all must
+	// match. If nil DefaultMatchers are used.
+	Matchers []Matcher
+	// Scrub, if set, is called on each interaction, after the default scrubbing,
+	// before it is recorded; and on each request before it is matched.
+	Scrub func(*Interaction)
+
+	mutex    sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New returns a recorder for the cassette at path. In replay mode the cassette must exist;
+// in record mode it is replaced once the recorder is stopped.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode, cassette: new(Cassette)}
+	if mode == ModeReplay {
+		cassette, err := Load(path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = cassette
+		r.used = make([]bool, len(cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Cassette returns the cassette being recorded or replayed
+func (r *Recorder) Cassette() *Cassette { return r.cassette }
+
+// Stop saves the cassette in record mode
+func (r *Recorder) Stop() error {
+	if r.Mode != ModeRecord {
+		return nil
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.cassette.Save(r.Path)
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	interaction := Interaction{Request: Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	}}
+
+	if r.Mode == ModeReplay {
+		r.scrub(&interaction)
+		recorded, err := r.replay(interaction.Request)
+		if err != nil {
+			return nil, err
+		}
+		return recorded.Response.response(req), nil
+	}
+
+	sent := req.Clone(req.Context())
+	sent.Body = io.NopCloser(bytes.NewReader(body))
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	res, err := transport.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	interaction.Response = Response{StatusCode: res.StatusCode, Header: res.Header.Clone(), Body: resBody}
+	r.scrub(&interaction)
+	r.mutex.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mutex.Unlock()
+	return res, nil
+}
+
+// replay returns the first unused interaction matching req
+func (r *Recorder) replay(req Request) (*Interaction, error) {
+	matchers := r.Matchers
+	if matchers == nil {
+		matchers = DefaultMatchers
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+NEXT:
+	for i := range r.cassette.Interactions {
+		if r.used[i] {
+			continue
+		}
+		for _, match := range matchers {
+			if !match(req, r.cassette.Interactions[i].Request) {
+				continue NEXT
+			}
+		}
+		r.used[i] = true
+		return &r.cassette.Interactions[i], nil
+	}
+	return nil, fmt.Errorf("%w for %v %v in %v", ErrNoInteraction, req.Method, req.URL, r.Path)
+}
+
+// scrub redacts the secrets of the interaction
+func (r *Recorder) scrub(interaction *Interaction) {
+	req, res := &interaction.Request, &interaction.Response
+	if u, err := url.Parse(req.URL); err == nil {
+		req.URL = api.RedactURL(u)
+	}
+	req.Header = api.RedactHeader(req.Header)
+	if utf8.Valid(req.Body) {
+		req.Body = Body(api.RedactBody(string(req.Body), req.Header.Get("Content-Type")))
+	}
+	if res.Header.Get("Set-Cookie") != "" {
+		res.Header.Set("Set-Cookie", oauth.Redacted)
+	}
+	if len(res.Body) > 0 && utf8.Valid(res.Body) {
+		res.Body = Body(api.RedactBody(string(res.Body), res.Header.Get("Content-Type")))
+	}
+	if r.Scrub != nil {
+		r.Scrub(interaction)
+	}
+}
+
+// response returns the http response of the recorded response to req
+func (res Response) response(req *http.Request) *http.Response {
+	header := res.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	// the body may have been scrubbed
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", res.StatusCode, http.StatusText(res.StatusCode)),
+		StatusCode:    res.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(res.Body)),
+		ContentLength: int64(len(res.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,151 @@
+package recorder_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/api/recorder"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/twolegged"
+)
+
+// newClient returns a client authenticating with 2-legged tokens, sending its traffic through rec
+func newClient(host string, rec *recorder.Recorder) *api.Client {
+	auth := twolegged.NewAuth("the-client", "secret-client-secret")
+	auth.Host = host
+	auth.HTTPClient = &http.Client{Transport: rec}
+	client := api.NewClient(auth)
+	client.Client.Transport = rec
+	return client
+}
+
+func TestRecorder(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/authentication/v2/token", "/authentication/v1/authenticate":
+			w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"secret-access-token"}`))
+		case "/oss/v2/buckets":
+			if r.Method == http.MethodPost {
+				body, _ := io.ReadAll(r.Body)
+				w.Write(body)
+				return
+			}
+			w.Write([]byte(`{"items":[{"bucketKey":"` + r.URL.Query().Get("region") + `"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	path := filepath.Join(t.TempDir(), "cassettes", "buckets.json")
+
+	type bucket struct {
+		BucketKey string `json:"bucketKey"`
+	}
+	type buckets struct {
+		Items []bucket `json:"items"`
+	}
+	list := func(client *api.Client) (result buckets, err error) {
+		err = client.Get(context.Background(), scopes.BucketRead, []string{"oss", "v2", "buckets"}, &result, regionFilter("EMEA"))
+		return result, err
+	}
+	create := func(client *api.Client, key string) (result bucket, err error) {
+		body := strings.NewReader(`{"bucketKey":"` + key + `"}`)
+		err = client.Post(context.Background(), scopes.BucketCreate, []string{"oss", "v2", "buckets"}, &result, api.ContentTypeJSON, body)
+		return result, err
+	}
+
+	rec, err := recorder.New(path, recorder.ModeRecord)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	client := newClient(server.URL, rec)
+	if _, err = list(client); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	for _, key := range []string{"first", "second"} {
+		if _, err = create(client, key); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+	}
+	if err = rec.Stop(); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	server.Close()
+
+	t.Run("Scrubbed", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		for _, secret := range []string{"secret-access-token", "secret-client-secret"} {
+			if bytes.Contains(data, []byte(secret)) {
+				t.Errorf("Expected %v to be scrubbed from the cassette", secret)
+			}
+		}
+	})
+
+	t.Run("Replay", func(t *testing.T) {
+		rec, err := recorder.New(path, recorder.ModeReplay)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		rec.Matchers = append(recorder.DefaultMatchers, recorder.MatchBody)
+		client := newClient("http://offline.invalid", rec)
+
+		result, err := list(client)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if len(result.Items) != 1 || result.Items[0].BucketKey != "EMEA" {
+			t.Errorf("Expected the recorded buckets, got %v", result)
+		}
+		// the bodies select the interactions, not the order
+		for _, key := range []string{"second", "first"} {
+			created, err := create(client, key)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+			if created.BucketKey != key {
+				t.Errorf("Expected bucket %v, got %v", key, created.BucketKey)
+			}
+		}
+		if _, err = create(client, "first"); !errors.Is(err, recorder.ErrNoInteraction) {
+			t.Errorf("Expected %v for a replayed interaction, got %v", recorder.ErrNoInteraction, err)
+		}
+	})
+
+	t.Run("Binary body", func(t *testing.T) {
+		cassette := recorder.Cassette{Interactions: []recorder.Interaction{{
+			Request:  recorder.Request{Method: http.MethodGet, URL: "https://example.com/object"},
+			Response: recorder.Response{StatusCode: http.StatusOK, Body: recorder.Body{0xff, 0x00, 0xfe}},
+		}}}
+		binaryPath := filepath.Join(t.TempDir(), "binary.json")
+		if err := cassette.Save(binaryPath); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		loaded, err := recorder.Load(binaryPath)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if body := loaded.Interactions[0].Response.Body; !bytes.Equal(body, []byte{0xff, 0x00, 0xfe}) {
+			t.Errorf("Expected the binary body, got %v", body)
+		}
+	})
+}
+
+type regionFilter string
+
+func (region regionFilter) Add(values url.Values) error {
+	values.Set("region", string(region))
+	return nil
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// ResponseMeta is the metadata of a response: its status and headers
+type ResponseMeta struct {
+	// StatusCode is the exact status, g.e. 201 or 202
+	StatusCode int
+	Header     http.Header
+	// RequestID is the id Forge gave the request, for support requests
+	RequestID string
+	// ETag of the resource
+	ETag string
+	// ContentRange of a partial response, g.e. bytes 0-1023/4096
+	ContentRange string
+	// RetryAfter is how long Forge asked to wait before retrying, 0 if it did not
+	RetryAfter time.Duration
+}
+
+// newResponseMeta returns the metadata of res
+func newResponseMeta(res *http.Response) ResponseMeta {
+	meta := ResponseMeta{
+		StatusCode:   res.StatusCode,
+		Header:       res.Header.Clone(),
+		ETag:         res.Header.Get("ETag"),
+		ContentRange: res.Header.Get("Content-Range"),
+	}
+	for _, name := range requestIDHeaders {
+		if meta.RequestID = res.Header.Get(name); meta.RequestID != "" {
+			break
+		}
+	}
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		meta.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return meta
+}
+
+// setResponseMeta fills the ResponseMeta of the call options of ctx, if any, with the metadata of res
+func setResponseMeta(ctx context.Context, res *http.Response) {
+	if meta := CallOptionsFrom(ctx).Meta; meta != nil && res != nil {
+		*meta = newResponseMeta(res)
+	}
+}
+
+// ADS returns the x-ads-* headers of the response, g.e. x-ads-app-identifier
+func (meta *ResponseMeta) ADS() http.Header {
+	ads := make(http.Header)
+	for name, values := range meta.Header {
+		if strings.HasPrefix(strings.ToLower(name), "x-ads-") {
+			ads[name] = values
+		}
+	}
+	return ads
+}
+
+// RateLimit returns the values of the X-RateLimit-Limit and X-RateLimit-Remaining
+// headers; ok is false if the response has none.
+func (meta *ResponseMeta) RateLimit() (limit, remaining int, ok bool) {
+	limit, errLimit := strconv.Atoi(meta.Header.Get("X-RateLimit-Limit"))
+	remaining, errRemaining := strconv.Atoi(meta.Header.Get("X-RateLimit-Remaining"))
+	return limit, remaining, errLimit == nil && errRemaining == nil
+}
+
+// CallOption configures a call of the high-level APIs, g.e.
+//
+//	var meta api.ResponseMeta
+//	details, err := bucketAPI.GetBucketDetails("bucket", api.WithResponseMeta(&meta))
+type CallOption func(*CallOptions)
+
+// CallOptions are the options of a call
+type CallOptions struct {
+	// Meta, if set, is filled with the metadata of the response; for retried
+	// requests it is the one of the last response.
+	Meta *ResponseMeta
+	// Context, if set, is the context the call is made with, g.e. to cancel it or to
+	// carry a tenant or a user id. See WithContext.
+	Context context.Context
+	// Filters are added to the query of the calls that take them. See WithFilters.
+	Filters []Filterer
+	// Header is added to the requests of the call. See WithHeader.
+	Header http.Header
+}
+
+// WithResponseMeta fills meta with the metadata of the response
+func WithResponseMeta(meta *ResponseMeta) CallOption {
+	return func(options *CallOptions) { options.Meta = meta }
+}
+
+// WithContext makes the call with ctx, instead of context.Background. g.e.
+//
+//	ctx := tenant.WithTenant(ctx, "acme")
+//	hub, err := hubAPI.GetHubDetails(hubKey, api.WithContext(ctx))
+func WithContext(ctx context.Context) CallOption {
+	return func(options *CallOptions) { options.Context = ctx }
+}
+
+// WithHeader sets the header on the requests of the call
+func WithHeader(key, value string) CallOption {
+	return func(options *CallOptions) {
+		// the header may be shared with the options of a parent context
+		header := options.Header.Clone()
+		if header == nil {
+			header = make(http.Header)
+		}
+		header.Set(key, value)
+		options.Header = header
+	}
+}
+
+// WithFilters adds the filters to the query of the calls that take them, g.e.
+//
+//	contents, err := folderAPI.GetFolderContents(project, folder, api.WithFilters(&dm.FolderContentsFilters{Include: filters.Include{"tip"}}))
+func WithFilters(filters ...Filterer) CallOption {
+	return func(options *CallOptions) { options.Filters = append(options.Filters, filters...) }
+}
+
+// CallFilters returns the filters of the options, see WithFilters
+func CallFilters(opts ...CallOption) []Filterer {
+	var options CallOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	return options.Filters
+}
+
+type callOptionsKey struct{}
+
+// WithCallOptions returns a copy of ctx carrying the options, added to the ones ctx
+// already carries. The requests made with the context apply them.
+func WithCallOptions(ctx context.Context, opts ...CallOption) context.Context {
+	if len(opts) == 0 {
+		return ctx
+	}
+	options := CallOptionsFrom(ctx)
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	// the call is already made with ctx, and adds its own filters
+	options.Context = nil
+	options.Filters = nil
+	return context.WithValue(ctx, callOptionsKey{}, options)
+}
+
+// CallOptionsFrom returns the options carried by ctx
+func CallOptionsFrom(ctx context.Context) CallOptions {
+	options, _ := ctx.Value(callOptionsKey{}).(CallOptions)
+	return options
+}
+
+// CallContext returns the context of a call of the operation with the options. It is
+// built on the context of WithContext, or on context.Background if there is none.
+func CallContext(op Operation, opts ...CallOption) context.Context {
+	var options CallOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+	ctx := options.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return WithCallOptions(op.Context(ctx), opts...)
+}
+
+// Do makes the request, decoding the JSON body of the response into a T. The metadata
+// is returned for every response, successful or not; it is nil if there was no response.
+func Do[T any](ctx context.Context, c *Client, method string, scope scopes.Scope, paths []string, filters []Filterer, contentType string, body io.Reader) (result T, meta *ResponseMeta, err error) {
+	meta = new(ResponseMeta)
+	err = c.DoRequest(WithCallOptions(ctx, WithResponseMeta(meta)), method, scope, paths, &result, filters, contentType, body)
+	if meta.StatusCode == 0 {
+		meta = nil
+	}
+	return result, meta, err
+}
@@ -0,0 +1,107 @@
+package api_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/api/filters"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+func TestDo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ads-request-id", "request-1")
+		w.Header().Set("x-ads-app-identifier", "app")
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", "99")
+		if r.URL.Path == "/missing" {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"bucketKey":"bucket"}`))
+	}))
+	defer server.Close()
+	auth := static.New("token")
+	auth.Host = server.URL
+	client := api.NewClient(auth)
+
+	type bucket struct {
+		BucketKey string `json:"bucketKey"`
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		result, meta, err := api.Do[bucket](context.Background(), client, http.MethodGet, 0, []string{"buckets"}, nil, api.ContentTypeJSON, nil)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if result.BucketKey != "bucket" {
+			t.Errorf("Expected bucket, got %v", result.BucketKey)
+		}
+		if meta == nil {
+			t.Fatalf("Expected the response metadata")
+		}
+		if meta.StatusCode != http.StatusOK || meta.RequestID != "request-1" || meta.ETag != `"v1"` {
+			t.Errorf("Expected status 200, request id and etag, got %+v", meta)
+		}
+		if ads := meta.ADS(); len(ads) != 2 || ads.Get("x-ads-app-identifier") != "app" {
+			t.Errorf("Expected the 2 x-ads headers, got %v", ads)
+		}
+		if limit, remaining, ok := meta.RateLimit(); !ok || limit != 100 || remaining != 99 {
+			t.Errorf("Expected rate limit 100 with 99 remaining, got %v %v %v", limit, remaining, ok)
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		_, meta, err := api.Do[bucket](context.Background(), client, http.MethodGet, 0, []string{"missing"}, nil, api.ContentTypeJSON, nil)
+		if err == nil {
+			t.Fatalf("Expected an error")
+		}
+		if meta == nil || meta.StatusCode != http.StatusNotFound || meta.RetryAfter != 3*time.Second {
+			t.Errorf("Expected the metadata of the 404, got %+v", meta)
+		}
+	})
+
+	t.Run("No response", func(t *testing.T) {
+		auth := static.New("token")
+		auth.Host = "http://127.0.0.1:1"
+		offline := api.NewClient(auth)
+		if _, meta, err := api.Do[bucket](context.Background(), offline, http.MethodGet, 0, []string{"buckets"}, nil, api.ContentTypeJSON, nil); err == nil || meta != nil {
+			t.Errorf("Expected an error without metadata, got %v %+v", err, meta)
+		}
+	})
+}
+
+func TestCallContext(t *testing.T) {
+	type userKey struct{}
+	op := api.Operation{Name: "test.call.context", Method: http.MethodGet}
+	var meta api.ResponseMeta
+	ctx := api.CallContext(op, api.WithContext(context.WithValue(context.Background(), userKey{}, "user-1")), api.WithResponseMeta(&meta))
+	if ctx.Value(userKey{}) != "user-1" {
+		t.Errorf("Expected the call context to be built on the given one")
+	}
+	if got, ok := api.OperationFrom(ctx); !ok || got.Name != op.Name {
+		t.Errorf("Expected operation %v, got %v", op.Name, got.Name)
+	}
+	if options := api.CallOptionsFrom(ctx); options.Meta != &meta || options.Context != nil {
+		t.Errorf("Unexpected call options %+v", options)
+	}
+	if ctx = api.CallContext(op); ctx.Value(userKey{}) != nil {
+		t.Errorf("Expected the call context to be built on context.Background")
+	}
+
+	t.Run("Filters", func(t *testing.T) {
+		opts := []api.CallOption{api.WithFilters(filters.QueryParam{Key: "a", Value: "1"}), api.WithFilters(filters.QueryParam{Key: "b", Value: "2"})}
+		if got := api.CallFilters(opts...); len(got) != 2 {
+			t.Errorf("Expected 2 filters, got %v", got)
+		}
+		if options := api.CallOptionsFrom(api.CallContext(op, opts...)); options.Filters != nil {
+			t.Errorf("Expected the filters not to be carried by the context, got %v", options.Filters)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryWait is how long to wait before retrying a rate limited request,
+// when Forge does not say how long to wait
+const DefaultRetryWait = 30 * time.Second
+
+// RetryPolicy controls how rate limited (429) requests are retried
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, 0 means no limit
+	MaxAttempts int
+	// Wait is how long to wait before retrying when the response has no Retry-After
+	// header, if 0 DefaultRetryWait is used
+	Wait time.Duration
+	// MaxWait caps the wait asked for by a Retry-After header, 0 means no cap
+	MaxWait time.Duration
+	// MaxBufferedBody is the size of the largest request body buffered so it can be
+	// sent again, for bodies that are not seekable. If 0 DefaultMaxBufferedBody is used.
+	MaxBufferedBody int64
+}
+
+func (policy RetryPolicy) maxBufferedBody() int64 {
+	if policy.MaxBufferedBody <= 0 {
+		return DefaultMaxBufferedBody
+	}
+	return policy.MaxBufferedBody
+}
+
+// DefaultRetryPolicy retries rate limited requests until they succeed
+var DefaultRetryPolicy = RetryPolicy{}
+
+// retry returns how long to wait before sending the request again, after attempt
+// attempts; ok is false if the request should not be retried.
+func (policy RetryPolicy) retry(attempt int, header http.Header) (wait time.Duration, ok bool) {
+	if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+		return 0, false
+	}
+	wait = policy.Wait
+	if wait == 0 {
+		wait = DefaultRetryWait
+	}
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && seconds >= 0 {
+		wait = time.Duration(seconds) * time.Second
+		if policy.MaxWait > 0 && wait > policy.MaxWait {
+			wait = policy.MaxWait
+		}
+	}
+	return wait, true
+}
+
+// sleep waits for d, returning early with the error of ctx if it is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
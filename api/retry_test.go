@@ -0,0 +1,207 @@
+package api_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+// newRateLimitedServer returns a server that rate limits the first limited requests
+func newRateLimitedServer(t *testing.T, limited int, requests *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		if *requests <= limited {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_Retry(t *testing.T) {
+	newClient := func(server *httptest.Server, policy api.RetryPolicy) *api.Client {
+		auth := static.New("token")
+		auth.Host = server.URL
+		client := api.NewClient(auth)
+		client.Retry = &policy
+		return client
+	}
+	paths := []string{"oss", "v2", "buckets"}
+
+	t.Run("Retry-After", func(t *testing.T) {
+		var requests int
+		client := newClient(newRateLimitedServer(t, 2, &requests), api.RetryPolicy{Wait: time.Hour})
+		if err := client.Get(context.Background(), 0, paths, nil); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if requests != 3 {
+			t.Errorf("Expected 3 requests, got %d", requests)
+		}
+	})
+
+	t.Run("Max attempts", func(t *testing.T) {
+		var requests int
+		client := newClient(newRateLimitedServer(t, 5, &requests), api.RetryPolicy{MaxAttempts: 2})
+		var errResult api.ErrResult
+		if err := client.Get(context.Background(), 0, paths, nil); !errors.As(err, &errResult) || !errResult.IsRateLimited() {
+			t.Fatalf("Expected a rate limited error, got %v", err)
+		}
+		if requests != 2 {
+			t.Errorf("Expected 2 requests, got %d", requests)
+		}
+	})
+
+	t.Run("JSON error body", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-Type", "application/json")
+			switch requests {
+			case 1:
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				w.Write([]byte(`{"reason":"Too many requests"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"reason":"Bucket not found"}`))
+			}
+		}))
+		defer server.Close()
+		client := newClient(server, api.RetryPolicy{})
+		err := client.Get(context.Background(), 0, paths, nil)
+		var errResult api.ErrResult
+		if !errors.As(err, &errResult) || !errResult.IsNotFound() || errResult.Reason != "Bucket not found" {
+			t.Errorf("Expected the not found error of the retried request, got %v", err)
+		}
+		if requests != 2 {
+			t.Errorf("Expected 2 requests, got %d", requests)
+		}
+	})
+
+	t.Run("Context done", func(t *testing.T) {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+		client := newClient(server, api.RetryPolicy{Wait: time.Hour})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := client.Get(ctx, 0, paths, nil); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Expected the wait to stop with the context, got %v", err)
+		}
+	})
+}
+
+func TestClient_RetryBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	auth := static.New("token")
+	auth.Host = server.URL
+	client := api.NewClient(auth)
+	paths := []string{"oss", "v2", "buckets", "bucket", "objects", "object"}
+	upload := api.Operation{Name: "oss.objects.upload", Method: http.MethodPut}.Context(context.Background())
+
+	t.Run("Buffered", func(t *testing.T) {
+		bodies = nil
+		// a reader that is not seekable
+		body := io.MultiReader(strings.NewReader("content"))
+		if err := client.Put(upload, 0, paths, nil, "application/octet-stream", body); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if len(bodies) != 2 || bodies[0] != "content" || bodies[1] != "content" {
+			t.Errorf("Expected the body to be sent twice, got %q", bodies)
+		}
+	})
+
+	t.Run("Seekable", func(t *testing.T) {
+		bodies = nil
+		body := strings.NewReader("skipped content")
+		body.Seek(int64(len("skipped ")), io.SeekStart)
+		if err := client.Put(upload, 0, paths, nil, "application/octet-stream", body); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if len(bodies) != 2 || bodies[0] != "content" || bodies[1] != "content" {
+			t.Errorf("Expected the body to be rewound to its offset, got %q", bodies)
+		}
+	})
+
+	t.Run("File", func(t *testing.T) {
+		bodies = nil
+		path := filepath.Join(t.TempDir(), "object")
+		if err := os.WriteFile(path, []byte("content"), 0o644); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		defer file.Close()
+		if err = client.Put(upload, 0, paths, nil, "application/octet-stream", file); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if len(bodies) != 2 || bodies[0] != "content" || bodies[1] != "content" {
+			t.Errorf("Expected the file to be sent twice, got %q", bodies)
+		}
+	})
+
+	t.Run("Too large", func(t *testing.T) {
+		bodies = nil
+		client := api.NewClient(auth)
+		client.Retry = &api.RetryPolicy{MaxBufferedBody: 3}
+		err := client.Put(upload, 0, paths, nil, "application/octet-stream", io.MultiReader(strings.NewReader("content")))
+		var errResult api.ErrResult
+		if !errors.Is(err, api.ErrBodyNotReplayable) || !errors.As(err, &errResult) || !errResult.IsRateLimited() {
+			t.Errorf("Expected %v for the rate limited request, got %v", api.ErrBodyNotReplayable, err)
+		}
+		if len(bodies) != 1 || bodies[0] != "content" {
+			t.Errorf("Expected the whole body to be sent once, got %q", bodies)
+		}
+	})
+
+	t.Run("Not idempotent", func(t *testing.T) {
+		bodies = nil
+		create := api.Operation{Name: "recap.photoscene.create", Method: http.MethodPost}
+		err := client.Post(create.Context(context.Background()), 0, paths, nil, api.ContentTypeJSON, strings.NewReader("{}"))
+		if !errors.Is(err, api.ErrNotIdempotent) {
+			t.Errorf("Expected %v, got %v", api.ErrNotIdempotent, err)
+		}
+		if len(bodies) != 1 {
+			t.Errorf("Expected 1 request, got %d", len(bodies))
+		}
+
+		bodies = nil
+		create.Idempotent = true
+		if err = client.Post(create.Context(context.Background()), 0, paths, nil, api.ContentTypeJSON, strings.NewReader("{}")); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if len(bodies) != 2 {
+			t.Errorf("Expected the idempotent operation to be retried, got %d requests", len(bodies))
+		}
+	})
+}
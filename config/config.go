@@ -0,0 +1,248 @@
+// Package config holds, in one place, the settings shared by all the Forge APIs:
+// the host, region, api paths, timeouts, retry policy and credentials.
+//
+// A configuration can be loaded from a YAML or JSON file, with optional named
+// profiles, and overridden by the environment:
+//
+//	cfg, err := config.Load("forge.yaml", "staging")
+//	bucketAPI, err := dm.NewBucketAPIFromConfig(cfg)
+//
+// with forge.yaml:
+//
+//	host: https://developer.api.autodesk.com
+//	timeout: 30s
+//	retry:
+//	  max_attempts: 5
+//	profiles:
+//	  staging:
+//	    host: https://staging.example.com
+//	  emea:
+//	    region: EMEA
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/credentials"
+	"github.com/gdey/forge-api-go-client/oauth/twolegged"
+)
+
+// Region is the geographical region the data is stored and processed in
+type Region string
+
+const (
+	// RegionUS is the default region
+	RegionUS = Region("US")
+	// RegionEMEA is the Europe, Middle East and Africa region
+	RegionEMEA = Region("EMEA")
+)
+
+// Normalize returns the region in upper case, with an empty region being RegionUS
+func (region Region) Normalize() Region {
+	if region == "" {
+		return RegionUS
+	}
+	return Region(strings.ToUpper(string(region)))
+}
+
+// IsValid returns true for the known regions
+func (region Region) IsValid() bool {
+	switch region.Normalize() {
+	case RegionUS, RegionEMEA:
+		return true
+	default:
+		return false
+	}
+}
+
+// Duration is a time.Duration encoded as a string, g.e. "30s"
+type Duration time.Duration
+
+func (d Duration) MarshalText() ([]byte, error) { return []byte(time.Duration(d).String()), nil }
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	duration, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// Paths are the base paths of each of the APIs; empty paths use the defaults of the
+// API, which may depend on the Region.
+type Paths struct {
+	Authentication  string `json:"authentication,omitempty" yaml:"authentication,omitempty"`
+	Buckets         string `json:"buckets,omitempty" yaml:"buckets,omitempty"`
+	Hubs            string `json:"hubs,omitempty" yaml:"hubs,omitempty"`
+	Folders         string `json:"folders,omitempty" yaml:"folders,omitempty"`
+	ModelDerivative string `json:"model_derivative,omitempty" yaml:"model_derivative,omitempty"`
+	Recap           string `json:"recap,omitempty" yaml:"recap,omitempty"`
+}
+
+// Retry reflects api.RetryPolicy
+type Retry struct {
+	MaxAttempts int      `json:"max_attempts,omitempty" yaml:"max_attempts,omitempty"`
+	Wait        Duration `json:"wait,omitempty" yaml:"wait,omitempty"`
+	MaxWait     Duration `json:"max_wait,omitempty" yaml:"max_wait,omitempty"`
+}
+
+// Policy returns the api.RetryPolicy
+func (r Retry) Policy() api.RetryPolicy {
+	return api.RetryPolicy{
+		MaxAttempts: r.MaxAttempts,
+		Wait:        time.Duration(r.Wait),
+		MaxWait:     time.Duration(r.MaxWait),
+	}
+}
+
+// Credentials of the app. If the client id is empty, the credentials are retrieved
+// through the default credentials chain, see credentials.DefaultChain.
+type Credentials struct {
+	ClientID     string       `json:"client_id,omitempty" yaml:"client_id,omitempty"`
+	ClientSecret oauth.Secret `json:"client_secret,omitempty" yaml:"client_secret,omitempty"`
+}
+
+// Config holds the settings shared by the APIs
+type Config struct {
+	// Host of the APIs, if empty oauth.DefaultHost is used
+	Host string `json:"host,omitempty" yaml:"host,omitempty"`
+	// Region of the data, it selects the Model Derivative path and the region of the OSS buckets
+	Region Region `json:"region,omitempty" yaml:"region,omitempty"`
+	Paths  Paths  `json:"paths,omitempty" yaml:"paths,omitempty"`
+	// Timeout of each http request, 0 means no timeout
+	Timeout     Duration    `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Retry       Retry       `json:"retry,omitempty" yaml:"retry,omitempty"`
+	Credentials Credentials `json:"credentials,omitempty" yaml:"credentials,omitempty"`
+}
+
+// Validate checks the configuration for errors
+func (cfg Config) Validate() error {
+	if !cfg.Region.IsValid() {
+		return fmt.Errorf("unknown region %q, expected %v or %v", cfg.Region, RegionUS, RegionEMEA)
+	}
+	if cfg.Timeout < 0 {
+		return fmt.Errorf("negative timeout %v", time.Duration(cfg.Timeout))
+	}
+	if cfg.Credentials.ClientID != "" && cfg.Credentials.ClientSecret == "" {
+		return fmt.Errorf("client secret is required with client id %q", cfg.Credentials.ClientID)
+	}
+	return nil
+}
+
+// Merge returns cfg with the non zero values of override applied
+func (cfg Config) Merge(override Config) Config {
+	str := func(value *string, override string) {
+		if override != "" {
+			*value = override
+		}
+	}
+	str(&cfg.Host, override.Host)
+	if override.Region != "" {
+		cfg.Region = override.Region
+	}
+	str(&cfg.Paths.Authentication, override.Paths.Authentication)
+	str(&cfg.Paths.Buckets, override.Paths.Buckets)
+	str(&cfg.Paths.Hubs, override.Paths.Hubs)
+	str(&cfg.Paths.Folders, override.Paths.Folders)
+	str(&cfg.Paths.ModelDerivative, override.Paths.ModelDerivative)
+	str(&cfg.Paths.Recap, override.Paths.Recap)
+	if override.Timeout != 0 {
+		cfg.Timeout = override.Timeout
+	}
+	if override.Retry.MaxAttempts != 0 {
+		cfg.Retry.MaxAttempts = override.Retry.MaxAttempts
+	}
+	if override.Retry.Wait != 0 {
+		cfg.Retry.Wait = override.Retry.Wait
+	}
+	if override.Retry.MaxWait != 0 {
+		cfg.Retry.MaxWait = override.Retry.MaxWait
+	}
+	if override.Credentials.ClientID != "" {
+		// credentials go together, so are never mixed
+		cfg.Credentials = override.Credentials
+	}
+	return cfg
+}
+
+// HTTPClient returns an http client with the configured timeout
+func (cfg Config) HTTPClient() *http.Client {
+	return &http.Client{Timeout: time.Duration(cfg.Timeout)}
+}
+
+// AuthData returns the credentials, with the configured host and authentication path
+func (cfg Config) AuthData(ctx context.Context) (data oauth.AuthData, err error) {
+	if cfg.Credentials.ClientID != "" {
+		data = oauth.AuthData{ClientID: cfg.Credentials.ClientID, ClientSecret: cfg.Credentials.ClientSecret}
+	} else if data, err = credentials.Retrieve(ctx); err != nil {
+		return data, err
+	}
+	if cfg.Host != "" {
+		data.Host = strings.TrimSuffix(cfg.Host, "/")
+	}
+	if cfg.Paths.Authentication != "" {
+		data.AuthenticationPath = cfg.Paths.Authentication
+	}
+	return data, nil
+}
+
+// Auth returns a 2-legged authenticator for the configured credentials
+func (cfg Config) Auth(ctx context.Context) (twolegged.Auth, error) {
+	data, err := cfg.AuthData(ctx)
+	if err != nil {
+		return twolegged.Auth{}, err
+	}
+	return cfg.authFor(data), nil
+}
+
+func (cfg Config) authFor(data oauth.AuthData) twolegged.Auth {
+	auth := twolegged.NewAuth(data.ClientID, data.ClientSecret.Reveal())
+	auth.Host, auth.AuthenticationPath = data.Host, data.AuthenticationPath
+	auth.HTTPClient = cfg.HTTPClient()
+	return auth
+}
+
+// ClientFor returns an api client using auth, with the configured timeout and retry policy
+func (cfg Config) ClientFor(auth oauth.ForgeAuthenticator) *api.Client {
+	client := api.NewClient(auth)
+	client.Client = *cfg.HTTPClient()
+	policy := cfg.Retry.Policy()
+	client.Retry = &policy
+	return client
+}
+
+// clientKey identifies the clients returned by Client
+type clientKey struct {
+	cfg  Config
+	data oauth.AuthData
+}
+
+// clients are the clients returned by Client, by clientKey
+var clients sync.Map
+
+// Client returns an api client authenticating with the configured credentials, see Auth.
+// Equal configurations, with the same credentials, get the same client; so the APIs built
+// from them share its connections and cached tokens.
+func (cfg Config) Client(ctx context.Context) (*api.Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	data, err := cfg.AuthData(ctx)
+	if err != nil {
+		return nil, err
+	}
+	key := clientKey{cfg: cfg, data: data}
+	if client, ok := clients.Load(key); ok {
+		return client.(*api.Client), nil
+	}
+	client, _ := clients.LoadOrStore(key, cfg.ClientFor(cfg.authFor(data)))
+	return client.(*api.Client), nil
+}
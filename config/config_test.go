@@ -0,0 +1,165 @@
+package config_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/config"
+	"github.com/gdey/forge-api-go-client/dm"
+	"github.com/gdey/forge-api-go-client/md"
+	"github.com/gdey/forge-api-go-client/oauth/credentials"
+)
+
+const yamlConfig = `
+host: https://developer.api.autodesk.com
+timeout: 30s
+retry:
+  max_attempts: 5
+credentials:
+  client_id: base-id
+  client_secret: base-secret
+profiles:
+  staging:
+    host: https://staging.example.com/
+    paths:
+      buckets: /oss/v2/staging
+  emea:
+    region: EMEA
+`
+
+// clearEnv unsets the environment variables read by config, for the duration of the test
+func clearEnv(t *testing.T) {
+	for _, name := range []string{
+		credentials.EnvHost, credentials.EnvAuthPath, credentials.EnvClientID, credentials.EnvClientSecret,
+		config.EnvRegion, config.EnvTimeout, config.EnvRetryMaxAttempts, config.EnvConfigProfile,
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	clearEnv(t)
+	path := writeFile(t, "forge.yaml", yamlConfig)
+
+	t.Run("Base", func(t *testing.T) {
+		cfg, err := config.Load(path, "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if cfg.Host != "https://developer.api.autodesk.com" || time.Duration(cfg.Timeout) != 30*time.Second ||
+			cfg.Retry.MaxAttempts != 5 || cfg.Credentials.ClientID != "base-id" {
+			t.Errorf("Unexpected config: %+v", cfg)
+		}
+	})
+
+	t.Run("Profile", func(t *testing.T) {
+		cfg, err := config.Load(path, "staging")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if cfg.Host != "https://staging.example.com/" || cfg.Paths.Buckets != "/oss/v2/staging" || cfg.Retry.MaxAttempts != 5 {
+			t.Errorf("Expected the staging profile over the base config, got %+v", cfg)
+		}
+		data, err := cfg.AuthData(context.Background())
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if data.Host != "https://staging.example.com" || data.ClientSecret.Reveal() != "base-secret" {
+			t.Errorf("Unexpected auth data: %+v", data)
+		}
+	})
+
+	t.Run("Environment", func(t *testing.T) {
+		t.Setenv(config.EnvConfigProfile, "emea")
+		t.Setenv(config.EnvTimeout, "5s")
+		cfg, err := config.Load(path, "")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if cfg.Region != config.RegionEMEA || time.Duration(cfg.Timeout) != 5*time.Second {
+			t.Errorf("Expected the emea profile and the env timeout, got %+v", cfg)
+		}
+		if path := md.APIPathFor(cfg); path != md.DefaultModelDerivativeEMEAPath {
+			t.Errorf("Expected the EMEA model derivative path, got %v", path)
+		}
+	})
+
+	t.Run("Unknown profile", func(t *testing.T) {
+		if _, err := config.Load(path, "production"); err == nil {
+			t.Errorf("Expected an error for an unknown profile")
+		}
+	})
+
+	t.Run("Invalid region", func(t *testing.T) {
+		t.Setenv(config.EnvRegion, "APAC")
+		if _, err := config.Load(path, ""); err == nil || !strings.Contains(err.Error(), "APAC") {
+			t.Errorf("Expected an error for the unknown region, got %v", err)
+		}
+	})
+}
+
+func TestParse_JSON(t *testing.T) {
+	file, err := config.Parse([]byte(`{"host": "http://localhost:8080", "timeout": "1m", "retry": {"wait": "2s"}}`), config.JSON)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if file.Host != "http://localhost:8080" || time.Duration(file.Timeout) != time.Minute ||
+		file.Retry.Policy().Wait != 2*time.Second {
+		t.Errorf("Unexpected config: %+v", file.Config)
+	}
+
+	if _, err = config.Parse([]byte(`{"hots": "http://localhost:8080"}`), config.JSON); err == nil {
+		t.Errorf("Expected an error for an unknown field")
+	}
+}
+
+func TestConfig_SecretRedacted(t *testing.T) {
+	cfg := config.Config{Credentials: config.Credentials{ClientID: "id", ClientSecret: "secret"}}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if strings.Contains(string(data), `"secret"`) {
+		t.Errorf("Expected the client secret to be redacted, got %s", data)
+	}
+}
+
+func TestConfig_Client(t *testing.T) {
+	cfg := config.Config{Region: config.RegionEMEA, Credentials: config.Credentials{ClientID: "id", ClientSecret: "secret"}}
+	bucketAPI, err := dm.NewBucketAPIFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	hubAPI, err := dm.NewHubAPIFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if bucketAPI.Client != hubAPI.Client {
+		t.Errorf("Expected the APIs of one configuration to share their client")
+	}
+	if bucketAPI.Region != config.RegionEMEA {
+		t.Errorf("Expected the EMEA region for the buckets, got %q", bucketAPI.Region)
+	}
+
+	cfg.Credentials.ClientID = "other-id"
+	client, err := cfg.Client(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if client == hubAPI.Client {
+		t.Errorf("Expected other credentials to get another client")
+	}
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/credentials"
+)
+
+const (
+	// EnvRegion is the environment variable holding the region
+	EnvRegion = "FORGE_REGION"
+	// EnvTimeout is the environment variable holding the request timeout, g.e. 30s
+	EnvTimeout = "FORGE_TIMEOUT"
+	// EnvRetryMaxAttempts is the environment variable holding the maximum attempts of rate limited requests
+	EnvRetryMaxAttempts = "FORGE_RETRY_MAX_ATTEMPTS"
+	// EnvConfigProfile is the environment variable naming the profile used by Load, when none is given
+	EnvConfigProfile = "FORGE_CONFIG_PROFILE"
+)
+
+// Format of a configuration file
+type Format uint8
+
+const (
+	YAML Format = iota
+	JSON
+)
+
+// FormatOf returns the format of the file based on its extension; .json is JSON,
+// everything else YAML
+func FormatOf(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return JSON
+	}
+	return YAML
+}
+
+// File is the content of a configuration file: a base configuration, and named
+// profiles that are merged over it.
+type File struct {
+	Config   `yaml:",inline"`
+	Profiles map[string]Config `json:"profiles,omitempty" yaml:"profiles,omitempty"`
+}
+
+// Profile returns the base configuration merged with the named profile; an empty
+// name returns the base configuration.
+func (file File) Profile(name string) (Config, error) {
+	if name == "" {
+		return file.Config, nil
+	}
+	profile, ok := file.Profiles[name]
+	if !ok {
+		return Config{}, fmt.Errorf("unknown config profile %q", name)
+	}
+	return file.Config.Merge(profile), nil
+}
+
+// Parse decodes a configuration file, rejecting unknown fields
+func Parse(data []byte, format Format) (file File, err error) {
+	switch format {
+	case JSON:
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&file)
+	default:
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&file)
+	}
+	if err != nil {
+		return file, fmt.Errorf("parsing config: %w", err)
+	}
+	return file, nil
+}
+
+// FromEnv returns the configuration set in the environment, see the Env constants
+// here and in the credentials package.
+func FromEnv() (cfg Config, err error) {
+	cfg.Host = os.Getenv(credentials.EnvHost)
+	cfg.Paths.Authentication = os.Getenv(credentials.EnvAuthPath)
+	cfg.Region = Region(os.Getenv(EnvRegion))
+	cfg.Credentials = Credentials{
+		ClientID:     os.Getenv(credentials.EnvClientID),
+		ClientSecret: oauth.Secret(os.Getenv(credentials.EnvClientSecret)),
+	}
+	if value := os.Getenv(EnvTimeout); value != "" {
+		if err = cfg.Timeout.UnmarshalText([]byte(value)); err != nil {
+			return cfg, fmt.Errorf("%v: %w", EnvTimeout, err)
+		}
+	}
+	if value := os.Getenv(EnvRetryMaxAttempts); value != "" {
+		if cfg.Retry.MaxAttempts, err = strconv.Atoi(value); err != nil {
+			return cfg, fmt.Errorf("%v: %w", EnvRetryMaxAttempts, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Load reads the configuration file, selects the profile and applies the
+// environment over it. If profile is empty FORGE_CONFIG_PROFILE is used.
+// If path is empty only the environment is used.
+func Load(path, profile string) (cfg Config, err error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		file, err := Parse(data, FormatOf(path))
+		if err != nil {
+			return cfg, fmt.Errorf("%v: %w", path, err)
+		}
+		if profile == "" {
+			profile = os.Getenv(EnvConfigProfile)
+		}
+		if cfg, err = file.Profile(profile); err != nil {
+			return cfg, fmt.Errorf("%v: %w", path, err)
+		}
+	}
+	env, err := FromEnv()
+	if err != nil {
+		return cfg, err
+	}
+	cfg = cfg.Merge(env)
+	return cfg, cfg.Validate()
+}
@@ -8,7 +8,7 @@ import (
 	"strconv"
 
 	clientapi "github.com/gdey/forge-api-go-client/api"
-	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/config"
 	"github.com/gdey/forge-api-go-client/oauth/twolegged"
 )
 
@@ -20,6 +20,9 @@ const (
 	BucketFilterKeyStartAt = "startAt"
 )
 
+// BucketHeaderRegion is the header with the region a bucket is created in
+const BucketHeaderRegion = "x-ads-region"
+
 const (
 	BucketFilterRegionUS   = BucketFilterRegion(0)
 	BucketFilterRegionEMEA = BucketFilterRegion(1)
@@ -93,6 +96,8 @@ func (filter *ListBucketsFilters) Add(values url.Values) (err error) {
 type BucketAPI struct {
 	Client  *clientapi.Client
 	APIPath string
+	// Region the buckets are created in and listed from, if empty the Forge default, US, is used
+	Region config.Region
 }
 
 func (api BucketAPI) Path(paths ...string) []string {
@@ -110,6 +115,15 @@ func NewBucketAPIWithCredentials(ClientID string, ClientSecret string) BucketAPI
 	}
 }
 
+// NewBucketAPIFromConfig returns a Bucket API client for the host, paths, region and credentials of cfg
+func NewBucketAPIFromConfig(cfg config.Config) (BucketAPI, error) {
+	client, err := cfg.Client(context.Background())
+	if err != nil {
+		return BucketAPI{}, err
+	}
+	return BucketAPI{Client: client, APIPath: cfg.Paths.Buckets, Region: cfg.Region}, nil
+}
+
 // CreateBucketRequest contains the data necessary to be passed upon bucket creation
 type CreateBucketRequest struct {
 	BucketKey string `json:"bucketKey"`
@@ -138,8 +152,11 @@ type ListedBuckets struct {
 	Next string `json:"next"`
 }
 
-// CreateBucket creates and returns details of created bucket, or an error on failure
-func (api BucketAPI) CreateBucket(bucketKey, policyKey string) (result BucketDetails, err error) {
+// CreateBucket creates, in the Region of the api, and returns details of created bucket, or an error on failure
+func (api BucketAPI) CreateBucket(bucketKey, policyKey string, opts ...clientapi.CallOption) (result BucketDetails, err error) {
+	if api.Region != "" {
+		opts = append([]clientapi.CallOption{clientapi.WithHeader(BucketHeaderRegion, string(api.Region.Normalize()))}, opts...)
+	}
 
 	body, err := json.Marshal(
 		CreateBucketRequest{
@@ -150,8 +167,8 @@ func (api BucketAPI) CreateBucket(bucketKey, policyKey string) (result BucketDet
 		return result, err
 	}
 	err = api.Client.Post(
-		context.Background(),
-		scopes.BucketRead,
+		clientapi.CallContext(OpCreateBucket, opts...),
+		OpCreateBucket.Scope,
 		api.Path(),
 		&result,
 		clientapi.ContentTypeJSON,
@@ -162,19 +179,28 @@ func (api BucketAPI) CreateBucket(bucketKey, policyKey string) (result BucketDet
 
 // DeleteBucket deletes bucket given its key.
 // 	WARNING: The bucket delete call is undocumented.
-func (api BucketAPI) DeleteBucket(bucketKey string) error {
+func (api BucketAPI) DeleteBucket(bucketKey string, opts ...clientapi.CallOption) error {
 	return api.Client.Delete(
-		context.Background(),
-		scopes.BucketRead,
+		clientapi.CallContext(OpDeleteBucket, opts...),
+		OpDeleteBucket.Scope,
 		api.Path(bucketKey),
 	)
 }
 
-// ListBuckets returns a list of all buckets created or associated with Forge secrets used for token creation
-func (api BucketAPI) ListBuckets(filters *ListBucketsFilters) (result ListedBuckets, err error) {
+// ListBuckets returns a list of all buckets created or associated with Forge secrets used for token creation.
+// Without a region in the filters, the buckets of the Region of the api are listed.
+func (api BucketAPI) ListBuckets(filters *ListBucketsFilters, opts ...clientapi.CallOption) (result ListedBuckets, err error) {
+	if api.Region.Normalize() == config.RegionEMEA && (filters == nil || filters.Region == BucketFilterRegionUS) {
+		var regional ListBucketsFilters
+		if filters != nil {
+			regional = *filters
+		}
+		regional.Region = BucketFilterRegionEMEA
+		filters = &regional
+	}
 	err = api.Client.Get(
-		context.Background(),
-		scopes.BucketRead,
+		clientapi.CallContext(OpListBuckets, opts...),
+		OpListBuckets.Scope,
 		api.Path(),
 		&result,
 		filters,
@@ -183,10 +209,10 @@ func (api BucketAPI) ListBuckets(filters *ListBucketsFilters) (result ListedBuck
 }
 
 // GetBucketDetails returns information associated to a bucket. See BucketDetails struct.
-func (api BucketAPI) GetBucketDetails(bucketKey string) (result BucketDetails, err error) {
+func (api BucketAPI) GetBucketDetails(bucketKey string, opts ...clientapi.CallOption) (result BucketDetails, err error) {
 	err = api.Client.Get(
-		context.Background(),
-		scopes.BucketRead,
+		clientapi.CallContext(OpGetBucketDetails, opts...),
+		OpGetBucketDetails.Scope,
 		api.Path(bucketKey, "details"),
 		&result,
 	)
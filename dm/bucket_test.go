@@ -3,19 +3,22 @@ package dm_test
 import (
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/config"
 	"github.com/gdey/forge-api-go-client/dm"
 	"github.com/gdey/forge-api-go-client/env"
+	"github.com/gdey/forge-api-go-client/oauth/static"
 )
 
 func TestBucketAPI_CreateBucket(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	bucketAPI := dm.NewBucketAPIWithCredentials(clientID, clientSecret)
+	bucketAPI := dm.BucketAPI{Client: env.GetClientTest(t)}
 
 	t.Run("Create a bucket", func(t *testing.T) {
 		_, err := bucketAPI.CreateBucket("go_testing_bucket", "transient")
@@ -53,9 +56,7 @@ func TestBucketAPI_CreateBucket(t *testing.T) {
 func TestBucketAPI_GetBucketDetails(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	bucketAPI := dm.NewBucketAPIWithCredentials(clientID, clientSecret)
+	bucketAPI := dm.BucketAPI{Client: env.GetClientTest(t)}
 
 	testBucketKey := "my_test_bucket_key_for_go"
 
@@ -95,9 +96,7 @@ func TestBucketAPI_GetBucketDetails(t *testing.T) {
 func TestBucketAPI_ListBuckets(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	bucketAPI := dm.NewBucketAPIWithCredentials(clientID, clientSecret)
+	bucketAPI := dm.BucketAPI{Client: env.GetClientTest(t)}
 
 	t.Run("List available buckets", func(t *testing.T) {
 		_, err := bucketAPI.ListBuckets(nil)
@@ -162,3 +161,37 @@ func ExampleBucketAPI_CreateBucket() {
 		bucket.PolicyKey)
 
 }
+
+func TestBucketAPI_Region(t *testing.T) {
+	var header, region string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header, region = r.Header.Get(dm.BucketHeaderRegion), r.URL.Query().Get(dm.BucketFilterKeyRegion)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	auth := static.New("token")
+	auth.Host = server.URL
+	bucketAPI := dm.BucketAPI{Client: api.NewClient(auth), Region: "emea"}
+
+	if _, err := bucketAPI.CreateBucket("bucket", "transient"); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if header != "EMEA" {
+		t.Errorf("Expected the bucket to be created in EMEA, got %q", header)
+	}
+	if _, err := bucketAPI.ListBuckets(&dm.ListBucketsFilters{Limit: 20}); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if region != "EMEA" {
+		t.Errorf("Expected the EMEA buckets to be listed, got %q", region)
+	}
+
+	bucketAPI.Region = config.RegionUS
+	if _, err := bucketAPI.ListBuckets(nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if region != "" {
+		t.Errorf("Expected no region filter for US, got %q", region)
+	}
+}
@@ -14,6 +14,79 @@ type ForgeResponseArray struct {
 	Included *[]Data `json:"included, omitempty"`
 }
 
+// findIncluded returns the resource of the type and id in the included resources
+func findIncluded(included *[]Data, typ, id string) (Data, bool) {
+	if included == nil {
+		return Data{}, false
+	}
+	for _, data := range *included {
+		if data.Type == typ && data.Id == id {
+			return data, true
+		}
+	}
+	return Data{}, false
+}
+
+// relatedData returns the resource a relationship refers to, if the relationship has one
+func relatedData(rel *RelatedLinks) (typ, id string, ok bool) {
+	if rel == nil || rel.Data == nil {
+		return "", "", false
+	}
+	return rel.Data.Type, rel.Data.Id, true
+}
+
+// tipLinks returns the tip relationship of an item
+func tipLinks(item Data) *RelatedLinks {
+	if item.Relationships == nil {
+		return nil
+	}
+	return item.Relationships.Tip
+}
+
+// Resource returns the included resource of the type and id
+func (res ForgeResponseObject) Resource(typ, id string) (Data, bool) {
+	return findIncluded(res.Included, typ, id)
+}
+
+// Related returns the included resource the relationship refers to, g.e. Related(data.Relationships.Parent)
+func (res ForgeResponseObject) Related(rel *RelatedLinks) (Data, bool) {
+	typ, id, ok := relatedData(rel)
+	if !ok {
+		return Data{}, false
+	}
+	return res.Resource(typ, id)
+}
+
+// Tip returns the tip version of the item, included with filters.Include{"tip"}
+func (res ForgeResponseObject) Tip(item Data) (Data, bool) {
+	return res.Related(tipLinks(item))
+}
+
+// Resource returns the included resource of the type and id
+func (res ForgeResponseArray) Resource(typ, id string) (Data, bool) {
+	return findIncluded(res.Included, typ, id)
+}
+
+// Related returns the included resource the relationship refers to, g.e. Related(data.Relationships.Parent)
+func (res ForgeResponseArray) Related(rel *RelatedLinks) (Data, bool) {
+	typ, id, ok := relatedData(rel)
+	if !ok {
+		return Data{}, false
+	}
+	return res.Resource(typ, id)
+}
+
+// Tip returns the tip version of the item, included with filters.Include{"tip"}. g.e.
+//
+//	contents, err := folderAPI.GetFolderContents(project, folder, api.WithFilters(&dm.FolderContentsFilters{Include: filters.Include{"tip"}}))
+//	for _, item := range contents.Data {
+//		version, ok := contents.Tip(item)
+//		...
+//	}
+func (res ForgeResponseArray) Tip(item Data) (Data, bool) {
+	return res.Related(tipLinks(item))
+}
+
 type JsonAPI struct {
 	Version string `json:"version"`
 }
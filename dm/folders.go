@@ -3,9 +3,11 @@ package dm
 import (
 	"context"
 	"fmt"
+	"net/url"
 
 	clientapi "github.com/gdey/forge-api-go-client/api"
-	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/api/filters"
+	"github.com/gdey/forge-api-go-client/config"
 	"github.com/gdey/forge-api-go-client/oauth/twolegged"
 )
 
@@ -37,6 +39,15 @@ func NewFolderAPIWithCredentials(ClientID string, ClientSecret string) FolderAPI
 	}
 }
 
+// NewFolderAPIFromConfig returns a Folder API client for the host, paths and credentials of cfg
+func NewFolderAPIFromConfig(cfg config.Config) (FolderAPI, error) {
+	client, err := cfg.Client(context.Background())
+	if err != nil {
+		return FolderAPI{}, err
+	}
+	return FolderAPI{Client: client, APIPath: cfg.Paths.Folders}, nil
+}
+
 func (api FolderAPI) Path(paths ...string) []string {
 	if api.APIPath == "" {
 		return append([]string{DefaultFolderAPIPath}, paths...)
@@ -44,11 +55,11 @@ func (api FolderAPI) Path(paths ...string) []string {
 	return append([]string{api.APIPath}, paths...)
 }
 
-func (api FolderAPI) GetFolderDetails(projectKey, folderKey string) (result ForgeResponseObject, err error) {
+func (api FolderAPI) GetFolderDetails(projectKey, folderKey string, opts ...clientapi.CallOption) (result ForgeResponseObject, err error) {
 
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetFolderDetails, opts...),
+		OpGetFolderDetails.Scope,
 		api.Path(projectKey, "folders", folderKey),
 		&result,
 		nil,
@@ -56,21 +67,50 @@ func (api FolderAPI) GetFolderDetails(projectKey, folderKey string) (result Forg
 	return result, err
 }
 
-func (api FolderAPI) GetFolderContents(projectKey, folderKey string) (result ForgeResponseArray, err error) {
+type FolderContentsFilters struct {
+	Pagination    *filters.Page
+	Type          filters.Type
+	ExtensionType filters.ExtensionType
+	Hidden        filters.Hidden
+	// Where are filters on filters.FolderContentFields, g.e. from filters.Parse; they are validated when added
+	Where filters.Filters
+	// Include are the relationships returned with the contents, g.e. filters.Include{"tip"};
+	// see ForgeResponseArray.Tip
+	Include       filters.Include
+	IncludeHidden filters.IncludeHidden
+	Fields        filters.SparseFields
+	Sort          filters.Sort
+}
+
+func (filter *FolderContentsFilters) Add(values url.Values) error {
+	if filter == nil {
+		return nil
+	}
+	if err := filter.Where.Validate(filters.FolderContentFields); err != nil {
+		return err
+	}
+	return filters.RunAll(values, filter.Pagination, filter.Type, filter.ExtensionType, filter.Hidden, filter.Where,
+		filter.Include, filter.IncludeHidden, filter.Fields, filter.Sort)
+}
+
+// GetFolderContents returns the items and folders of the folder, filtered with
+// clientapi.WithFilters(&FolderContentsFilters{...}).
+// https://forge.autodesk.com/en/docs/data/v2/reference/http/projects-project_id-folders-folder_id-contents-GET/
+func (api FolderAPI) GetFolderContents(projectKey, folderKey string, opts ...clientapi.CallOption) (result ForgeResponseArray, err error) {
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetFolderContents, opts...),
+		OpGetFolderContents.Scope,
 		api.Path(projectKey, "folders", folderKey, "contents"),
 		&result,
-		nil,
+		clientapi.CallFilters(opts...)...,
 	)
 	return result, err
 }
 
-func (api FolderAPI) GetFolders(projectKey string) (result ForgeResponseArray, err error) {
+func (api FolderAPI) GetFolders(projectKey string, opts ...clientapi.CallOption) (result ForgeResponseArray, err error) {
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetFolders, opts...),
+		OpGetFolders.Scope,
 		api.Path(projectKey, "folders"),
 		&result,
 		nil,
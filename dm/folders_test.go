@@ -1,26 +1,25 @@
 package dm_test
 
 import (
-	"os"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/api/filters"
 	"github.com/gdey/forge-api-go-client/dm"
 	"github.com/gdey/forge-api-go-client/env"
+	"github.com/gdey/forge-api-go-client/oauth/static"
 )
 
 func TestFolderAPI_GetFolderDetails(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
+	folderAPI := dm.FolderAPI{Client: env.GetClientTest(t)}
 
-	folderAPI := dm.NewFolderAPIWithCredentials(clientID, clientSecret)
-
-	testProjectKey := os.Getenv("BIM_360_TEST_ACCOUNT_PROJECTKEY")
-	testFolderKey := os.Getenv("BIM_360_TEST_ACCOUNT_FOLDERKEY")
-
-	if testFolderKey == "" || testProjectKey == "" {
-		t.Skip("Test environment vars not set")
-	}
+	testProjectKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_PROJECTKEY")
+	testFolderKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_FOLDERKEY")
 
 	t.Run("List all folders for a given project", func(t *testing.T) {
 		_, err := folderAPI.GetFolderDetails(testProjectKey, testFolderKey)
@@ -34,12 +33,10 @@ func TestFolderAPI_GetFolderDetails(t *testing.T) {
 func TestFolderAPI_GetContents(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	folderAPI := dm.NewFolderAPIWithCredentials(clientID, clientSecret)
+	folderAPI := dm.FolderAPI{Client: env.GetClientTest(t)}
 
-	testProjectKey := os.Getenv("BIM_360_TEST_ACCOUNT_PROJECTKEY")
-	testFolderKey := os.Getenv("BIM_360_TEST_ACCOUNT_FOLDERKEY")
+	testProjectKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_PROJECTKEY")
+	testFolderKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_FOLDERKEY")
 
 	t.Run("Get folder contents", func(t *testing.T) {
 		_, err := folderAPI.GetFolderContents(testProjectKey, testFolderKey)
@@ -57,3 +54,70 @@ func TestFolderAPI_GetContents(t *testing.T) {
 		}
 	})
 }
+
+func TestFolderAPI_GetContentsIncluded(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		w.Write([]byte(`{
+			"data": [
+				{"type": "items", "id": "item-1", "relationships": {"tip": {"data": {"type": "versions", "id": "version-1"}}}},
+				{"type": "folders", "id": "folder-1"}
+			],
+			"included": [
+				{"type": "versions", "id": "version-1", "attributes": {"name": "plan.rvt", "versionNumber": 3}}
+			]
+		}`))
+	}))
+	defer server.Close()
+
+	auth := static.New("token")
+	auth.Host = server.URL
+	folderAPI := dm.FolderAPI{Client: api.NewClient(auth)}
+	contents, err := folderAPI.GetFolderContents("project", "folder", api.WithFilters(&dm.FolderContentsFilters{
+		Include: filters.Include{"tip"},
+		Sort:    filters.Sort{"-lastModifiedTime"},
+	}))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if query != "include=tip&sort=-lastModifiedTime" {
+		t.Errorf("Unexpected query %v", query)
+	}
+	if len(contents.Data) != 2 {
+		t.Fatalf("Expected 2 resources, got %d", len(contents.Data))
+	}
+	tip, ok := contents.Tip(contents.Data[0])
+	if !ok || tip.Id != "version-1" || tip.Attributes == nil || *tip.Attributes.VersionNumber != 3 {
+		t.Errorf("Expected the included version-1, got %+v", tip)
+	}
+	if _, ok = contents.Tip(contents.Data[1]); ok {
+		t.Errorf("Expected no tip for a folder")
+	}
+	if _, ok = contents.Resource("versions", "version-2"); ok {
+		t.Errorf("Expected no version-2")
+	}
+}
+
+func TestFolderAPI_GetContentsInvalidFilter(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"data": []}`))
+	}))
+	defer server.Close()
+
+	auth := static.New("token")
+	auth.Host = server.URL
+	folderAPI := dm.FolderAPI{Client: api.NewClient(auth)}
+	_, err := folderAPI.GetFolderContents("project", "folder", api.WithFilters(&dm.FolderContentsFilters{
+		Where: filters.Filters{{Name: "versionNumber", Comparison: filters.ComparisonContains, Values: []string{"3"}}},
+	}))
+	var invalid filters.ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Expected a ValidationError, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("Expected no request for an invalid filter, got %d", requests)
+	}
+}
@@ -8,7 +8,7 @@ import (
 
 	clientapi "github.com/gdey/forge-api-go-client/api"
 	"github.com/gdey/forge-api-go-client/api/filters"
-	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/config"
 	"github.com/gdey/forge-api-go-client/oauth/twolegged"
 )
 
@@ -65,13 +65,18 @@ type HubsFilters struct {
 	Type HubFilterType
 	ID   filters.ID
 	Name filters.Name
+	// Where are filters on filters.HubFields, g.e. from filters.Parse; they are validated when added
+	Where filters.Filters
 }
 
 func (filter *HubsFilters) Add(values url.Values) (err error) {
 	if filter == nil {
 		return nil
 	}
-	return filters.RunAll(values, filter.Type, filter.ID, filter.Name)
+	if err = filter.Where.Validate(filters.HubFields); err != nil {
+		return err
+	}
+	return filters.RunAll(values, filter.Type, filter.ID, filter.Name, filter.Where)
 }
 
 // HubAPI holds the necessary data for making calls to Forge Data Management service
@@ -89,6 +94,15 @@ func NewHubAPIWithCredentials(ClientID string, ClientSecret string) HubAPI {
 	}
 }
 
+// NewHubAPIFromConfig returns a Hub API client for the host, paths and credentials of cfg
+func NewHubAPIFromConfig(cfg config.Config) (HubAPI, error) {
+	client, err := cfg.Client(context.Background())
+	if err != nil {
+		return HubAPI{}, err
+	}
+	return HubAPI{Client: client, APIPath: cfg.Paths.Hubs}, nil
+}
+
 func (api HubAPI) Path(paths ...string) []string {
 	if api.APIPath == "" {
 		return append([]string{DefaultHubAPIPath}, paths...)
@@ -98,10 +112,10 @@ func (api HubAPI) Path(paths ...string) []string {
 
 // GetHubs returns a list of know hubs
 // ref: https://forge.autodesk.com/en/docs/data/v2/reference/http/hubs-GET/
-func (api HubAPI) GetHubs(hubFilters *HubsFilters) (result ForgeResponseArray, err error) {
+func (api HubAPI) GetHubs(hubFilters *HubsFilters, opts ...clientapi.CallOption) (result ForgeResponseArray, err error) {
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetHubs, opts...),
+		OpGetHubs.Scope,
 		api.Path(),
 		&result,
 		hubFilters,
@@ -110,10 +124,10 @@ func (api HubAPI) GetHubs(hubFilters *HubsFilters) (result ForgeResponseArray, e
 }
 
 // GetHubDetails returns the Details for the given hub
-func (api HubAPI) GetHubDetails(hubKey string) (result ForgeResponseObject, err error) {
+func (api HubAPI) GetHubDetails(hubKey string, opts ...clientapi.CallOption) (result ForgeResponseObject, err error) {
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetHubDetails, opts...),
+		OpGetHubDetails.Scope,
 		api.Path(hubKey),
 		&result,
 	)
@@ -10,9 +10,7 @@ import (
 func TestHubAPI_GetHubDetails(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	hubAPI := dm.NewHubAPIWithCredentials(clientID, clientSecret)
+	hubAPI := dm.HubAPI{Client: env.GetClientTest(t)}
 
 	// testHubKey := "my_test_hub_key_for_go"
 	testHubKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_HUBKEY")
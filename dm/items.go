@@ -1,18 +1,17 @@
 package dm
 
 import (
-	"context"
 	"net/url"
 
+	clientapi "github.com/gdey/forge-api-go-client/api"
 	"github.com/gdey/forge-api-go-client/api/filters"
-	"github.com/gdey/forge-api-go-client/oauth/scopes"
 )
 
-func (api FolderAPI) GetItemDetails(projectKey, itemKey string) (result ForgeResponseObject, err error) {
+func (api FolderAPI) GetItemDetails(projectKey, itemKey string, opts ...clientapi.CallOption) (result ForgeResponseObject, err error) {
 
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetItemDetails, opts...),
+		OpGetItemDetails.Scope,
 		api.Path(projectKey, "items", itemKey),
 		&result,
 		nil,
@@ -20,11 +19,11 @@ func (api FolderAPI) GetItemDetails(projectKey, itemKey string) (result ForgeRes
 	return result, err
 }
 
-func (api FolderAPI) GetItemTip(projectKey, itemKey string) (result ForgeResponseObject, err error) {
+func (api FolderAPI) GetItemTip(projectKey, itemKey string, opts ...clientapi.CallOption) (result ForgeResponseObject, err error) {
 
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetItemTip, opts...),
+		OpGetItemTip.Scope,
 		api.Path(projectKey, "items", itemKey, "tip"),
 		&result,
 		nil,
@@ -38,21 +37,29 @@ type ItemVersionFilters struct {
 	Type           filters.Type
 	ID             filters.ID
 	ExtensionType  filters.ExtensionType
+	// Where are filters on filters.ItemVersionFields, g.e. from filters.Parse; they are validated when added
+	Where  filters.Filters
+	Fields filters.SparseFields
+	Sort   filters.Sort
 }
 
 func (filter *ItemVersionFilters) Add(values url.Values) error {
 	if filter == nil {
 		return nil
 	}
-	return filters.RunAll(values, filter.Pagination, filter.ID, filter.Type, filter.ExtensionType, filter.VersionNumbers)
+	if err := filter.Where.Validate(filters.ItemVersionFields); err != nil {
+		return err
+	}
+	return filters.RunAll(values, filter.Pagination, filter.ID, filter.Type, filter.ExtensionType, filter.VersionNumbers, filter.Where,
+		filter.Fields, filter.Sort)
 }
 
 // https://forge.autodesk.com/en/docs/data/v2/reference/http/projects-project_id-items-item_id-versions-GET/
-func (api FolderAPI) GetItemVersions(projectKey, itemKey string, filter *ItemVersionFilters) (result ForgeResponseArray, err error) {
+func (api FolderAPI) GetItemVersions(projectKey, itemKey string, filter *ItemVersionFilters, opts ...clientapi.CallOption) (result ForgeResponseArray, err error) {
 
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetItemVersions, opts...),
+		OpGetItemVersions.Scope,
 		api.Path(projectKey, "items", itemKey, "versions"),
 		&result,
 		filter,
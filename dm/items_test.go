@@ -1,7 +1,6 @@
 package dm
 
 import (
-	"os"
 	"testing"
 
 	"github.com/gdey/forge-api-go-client/env"
@@ -10,12 +9,10 @@ import (
 func TestFolderAPI_GetItemDetails(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
+	folderAPI := FolderAPI{Client: env.GetClientTest(t)}
 
-	folderAPI := NewFolderAPIWithCredentials(clientID, clientSecret)
-
-	testProjectKey := os.Getenv("BIM_360_TEST_ACCOUNT_PROJECTKEY")
-	testItemKey := os.Getenv("BIM_360_TEST_ACCOUNT_ITEMKEY")
+	testProjectKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_PROJECTKEY")
+	testItemKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_ITEMKEY")
 
 	t.Run("List item details", func(t *testing.T) {
 		_, err := folderAPI.GetItemDetails(testProjectKey, testItemKey)
@@ -29,12 +26,10 @@ func TestFolderAPI_GetItemDetails(t *testing.T) {
 func TestFolderAPI_GetItemTip(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	folderAPI := NewFolderAPIWithCredentials(clientID, clientSecret)
+	folderAPI := FolderAPI{Client: env.GetClientTest(t)}
 
-	testProjectKey := os.Getenv("BIM_360_TEST_ACCOUNT_PROJECTKEY")
-	testItemKey := os.Getenv("BIM_360_TEST_ACCOUNT_ITEMKEY")
+	testProjectKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_PROJECTKEY")
+	testItemKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_ITEMKEY")
 
 	t.Run("List item details", func(t *testing.T) {
 		_, err := folderAPI.GetItemTip(testProjectKey, testItemKey)
@@ -48,12 +43,10 @@ func TestFolderAPI_GetItemTip(t *testing.T) {
 func TestFolderAPI_GetItemVersions(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	folderAPI := NewFolderAPIWithCredentials(clientID, clientSecret)
+	folderAPI := FolderAPI{Client: env.GetClientTest(t)}
 
-	testProjectKey := os.Getenv("BIM_360_TEST_ACCOUNT_PROJECTKEY")
-	testItemKey := os.Getenv("BIM_360_TEST_ACCOUNT_ITEMKEY")
+	testProjectKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_PROJECTKEY")
+	testItemKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_ITEMKEY")
 
 	t.Run("List item details", func(t *testing.T) {
 		_, err := folderAPI.GetItemVersions(testProjectKey, testItemKey, nil)
@@ -1,13 +1,11 @@
 package dm
 
 import (
-	"context"
 	"io"
 	"net/http"
 	"net/url"
 
 	clientapi "github.com/gdey/forge-api-go-client/api"
-	"github.com/gdey/forge-api-go-client/oauth/scopes"
 )
 
 // ObjectDetails reflects the data presented when uploading an object to a bucket or requesting details on object.
@@ -67,11 +65,11 @@ type BucketContent struct {
 
 // UploadObject adds to specified bucket the given data (can originate from a multipart-form or direct file read).
 // Return details on uploaded object, including the object URN. Check ObjectDetails struct.
-func (api BucketAPI) UploadObject(bucketKey string, objectName string, reader io.Reader) (result ObjectDetails, err error) {
+func (api BucketAPI) UploadObject(bucketKey string, objectName string, reader io.Reader, opts ...clientapi.CallOption) (result ObjectDetails, err error) {
 
 	err = api.Client.Put(
-		context.Background(),
-		scopes.DataWrite|scopes.DataCreate,
+		clientapi.CallContext(OpUploadObject, opts...),
+		OpUploadObject.Scope,
 		api.Path(bucketKey, "objects", objectName),
 		&result,
 		"",
@@ -85,10 +83,10 @@ func (api BucketAPI) UploadObject(bucketKey string, objectName string, reader io
 // Don't forget to close it!
 // https://forge.autodesk.com/en/docs/data/v2/reference/http/buckets-:bucketKey-objects-:objectName-GET/
 // TODO(gdey): Create DownloadObjectOptions Struct to set various Headers
-func (api BucketAPI) DownloadObject(bucketKey string, objectName string) (reader io.ReadCloser, err error) {
+func (api BucketAPI) DownloadObject(bucketKey string, objectName string, opts ...clientapi.CallOption) (reader io.ReadCloser, err error) {
 	res, err := api.Client.DoRawRequest(
-		context.Background(), "GET",
-		scopes.DataRead,
+		clientapi.CallContext(OpDownloadObject, opts...), OpDownloadObject.Method,
+		OpDownloadObject.Scope,
 		api.Path(bucketKey, "objects", objectName),
 		nil, nil, "", nil,
 	)
@@ -104,10 +102,10 @@ func (api BucketAPI) DownloadObject(bucketKey string, objectName string) (reader
 }
 
 // ListObjects returns the bucket contains along with details on each item.
-func (api BucketAPI) ListObjects(bucketKey string, filters *ListObjectsFilters) (result BucketContent, err error) {
+func (api BucketAPI) ListObjects(bucketKey string, filters *ListObjectsFilters, opts ...clientapi.CallOption) (result BucketContent, err error) {
 	err = api.Client.Get(
-		context.Background(),
-		scopes.BucketRead,
+		clientapi.CallContext(OpListObjects, opts...),
+		OpListObjects.Scope,
 		api.Path(bucketKey, "objects"),
 		&result,
 		filters,
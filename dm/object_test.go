@@ -11,9 +11,7 @@ import (
 
 func TestBucketAPI_ListObjects(t *testing.T) {
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	bucketAPI := dm.NewBucketAPIWithCredentials(clientID, clientSecret)
+	bucketAPI := dm.BucketAPI{Client: env.GetClientTest(t)}
 
 	// testBucketName := "just_a_test_bucket"
 	testBucketName := env.GetTest(t, "FORGE_OSS_TEST_BUCKET_KEY")
@@ -38,12 +36,10 @@ func TestBucketAPI_ListObjects(t *testing.T) {
 func TestBucketAPI_UploadObject(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	bucketAPI := dm.NewBucketAPIWithCredentials(clientID, clientSecret)
+	bucketAPI := dm.BucketAPI{Client: env.GetClientTest(t)}
 
 	tempBucket := "some_temp_bucket_for_testing"
-	testFilePath := "../assets/HelloWorld.rvt"
+	testFilePath := "../assets/TestFile.txt"
 
 	t.Run("Create a temp bucket to store an object", func(t *testing.T) {
 		_, err := bucketAPI.CreateBucket(tempBucket, "transient")
@@ -69,7 +65,7 @@ func TestBucketAPI_UploadObject(t *testing.T) {
 		}
 		defer file.Close()
 
-		result, err := bucketAPI.UploadObject(tempBucket, "temp_file.rvt", file) // doesn't want []byte as data
+		result, err := bucketAPI.UploadObject(tempBucket, "temp_file.txt", file) // doesn't want []byte as data
 
 		if err != nil {
 			t.Fatal("Could not upload the test object, got: ", err.Error())
@@ -90,9 +86,7 @@ func TestBucketAPI_UploadObject(t *testing.T) {
 
 func TestBucketAPI_DownloadObject(t *testing.T) {
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	bucketAPI := dm.NewBucketAPIWithCredentials(clientID, clientSecret)
+	bucketAPI := dm.BucketAPI{Client: env.GetClientTest(t)}
 
 	tempBucket := "test_bucket_for_download"
 	testFilePath := "../assets/TestFile.txt"
@@ -0,0 +1,71 @@
+package dm
+
+import (
+	"net/http"
+
+	clientapi "github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// Operations for the Object Storage Service (OSS)
+// ref: https://forge.autodesk.com/en/docs/data/v2/reference/http/
+var (
+	OpCreateBucket = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "oss.buckets.create", Method: http.MethodPost, Scope: scopes.BucketCreate, Idempotent: true,
+	})
+	OpDeleteBucket = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "oss.buckets.delete", Method: http.MethodDelete, Scope: scopes.BucketDelete,
+	})
+	OpListBuckets = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "oss.buckets.list", Method: http.MethodGet, Scope: scopes.BucketRead,
+	})
+	OpGetBucketDetails = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "oss.buckets.details", Method: http.MethodGet, Scope: scopes.BucketRead,
+	})
+	OpUploadObject = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "oss.objects.upload", Method: http.MethodPut, Scope: scopes.DataWrite,
+	})
+	OpDownloadObject = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "oss.objects.download", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpListObjects = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "oss.objects.list", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+)
+
+// Operations for the Data Management hubs, projects, folders and items
+var (
+	OpGetHubs = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.hubs.list", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetHubDetails = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.hubs.get", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpListProjects = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.projects.list", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetProjectDetails = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.projects.get", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetTopFolders = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.projects.topfolders", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetFolders = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.folders.list", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetFolderDetails = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.folders.get", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetFolderContents = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.folders.contents", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetItemDetails = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.items.get", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetItemTip = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.items.tip", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetItemVersions = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "dm.items.versions", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+)
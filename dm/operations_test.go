@@ -0,0 +1,70 @@
+package dm_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/api/apitest"
+	"github.com/gdey/forge-api-go-client/dm"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+func TestOperationScopes(t *testing.T) {
+	server := apitest.NewServer(t)
+	client := server.Client()
+	bucketAPI := dm.BucketAPI{Client: client}
+	hubAPI := dm.HubAPI{Client: client}
+	folderAPI := dm.FolderAPI{Client: client}
+
+	apitest.TestOperationScopes(t, server, []apitest.OperationCase{
+		{Op: dm.OpCreateBucket, Call: func() error { _, err := bucketAPI.CreateBucket("bucket", "transient"); return err }},
+		{Op: dm.OpDeleteBucket, Call: func() error { return bucketAPI.DeleteBucket("bucket") }},
+		{Op: dm.OpListBuckets, Call: func() error { _, err := bucketAPI.ListBuckets(nil); return err }},
+		{Op: dm.OpGetBucketDetails, Call: func() error { _, err := bucketAPI.GetBucketDetails("bucket"); return err }},
+		{Op: dm.OpUploadObject, Call: func() error {
+			_, err := bucketAPI.UploadObject("bucket", "object", bytes.NewBufferString("content"))
+			return err
+		}},
+		{Op: dm.OpDownloadObject, Call: func() error {
+			reader, err := bucketAPI.DownloadObject("bucket", "object")
+			if err == nil {
+				reader.Close()
+			}
+			return err
+		}},
+		{Op: dm.OpListObjects, Call: func() error { _, err := bucketAPI.ListObjects("bucket", nil); return err }},
+		{Op: dm.OpGetHubs, Call: func() error { _, err := hubAPI.GetHubs(nil); return err }},
+		{Op: dm.OpGetHubDetails, Call: func() error { _, err := hubAPI.GetHubDetails("hub"); return err }},
+		{Op: dm.OpListProjects, Call: func() error { _, err := hubAPI.ListProjects("hub", nil); return err }},
+		{Op: dm.OpGetProjectDetails, Call: func() error { _, err := hubAPI.GetProjectDetails("hub", "project"); return err }},
+		{Op: dm.OpGetTopFolders, Call: func() error { _, err := hubAPI.GetTopFolders("hub", "project"); return err }},
+		{Op: dm.OpGetFolders, Call: func() error { _, err := folderAPI.GetFolders("project"); return err }},
+		{Op: dm.OpGetFolderDetails, Call: func() error { _, err := folderAPI.GetFolderDetails("project", "folder"); return err }},
+		{Op: dm.OpGetFolderContents, Call: func() error { _, err := folderAPI.GetFolderContents("project", "folder"); return err }},
+		{Op: dm.OpGetItemDetails, Call: func() error { _, err := folderAPI.GetItemDetails("project", "item"); return err }},
+		{Op: dm.OpGetItemTip, Call: func() error { _, err := folderAPI.GetItemTip("project", "item"); return err }},
+		{Op: dm.OpGetItemVersions, Call: func() error { _, err := folderAPI.GetItemVersions("project", "item", nil); return err }},
+	})
+}
+
+func TestResponseMeta(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-ads-request-id", "request-1")
+		w.Write([]byte(`{"bucketKey":"bucket"}`))
+	}))
+	defer server.Close()
+
+	auth := static.New("token")
+	auth.Host = server.URL
+	bucketAPI := dm.BucketAPI{Client: api.NewClient(auth)}
+	var meta api.ResponseMeta
+	if _, err := bucketAPI.GetBucketDetails("bucket", api.WithResponseMeta(&meta)); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if meta.StatusCode != http.StatusOK || meta.RequestID != "request-1" {
+		t.Errorf("Expected status 200 and request id request-1, got %v and %v", meta.StatusCode, meta.RequestID)
+	}
+}
@@ -1,37 +1,25 @@
 package dm
 
 import (
-	"context"
 	"net/url"
 	"strconv"
 
-	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	clientapi "github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/api/filters"
 )
 
 const (
-	ProjectFilterKeyID        = "filter[id]"
-	ProjectFilterKeyType      = "filter[extension.type]"
+	ProjectFilterKeyID        = filters.KeyID
+	ProjectFilterKeyType      = filters.KeyExtensionType
 	ProjectFilterKeyPageNum   = "page[number]"
 	ProjectFilterKeyPageLimit = "page[limit]"
 )
 
-type ProjectFilterID []string
+// ProjectFilterID is the filters.ID used by the other Data Management filters
+type ProjectFilterID = filters.ID
 
-func (filter ProjectFilterID) Add(values url.Values) error {
-	for _, val := range filter {
-		values.Add(ProjectFilterKeyID, val)
-	}
-	return nil
-}
-
-type ProjectFilterType []string
-
-func (filter ProjectFilterType) Add(values url.Values) error {
-	for _, val := range filter {
-		values.Add(ProjectFilterKeyType, val)
-	}
-	return nil
-}
+// ProjectFilterType is the filters.ExtensionType used by the other Data Management filters
+type ProjectFilterType = filters.ExtensionType
 
 type ProjectFilterPageNumber int
 
@@ -58,12 +46,17 @@ type ListProjectFilters struct {
 	Limit  ProjectFilterPageLimit
 	ID     ProjectFilterID
 	Type   ProjectFilterType
+	// Where are filters on filters.ProjectFields, g.e. from filters.Parse; they are validated when added
+	Where filters.Filters
 }
 
 func (filter *ListProjectFilters) Add(values url.Values) (err error) {
 	if filter == nil {
 		return nil
 	}
+	if err = filter.Where.Validate(filters.ProjectFields); err != nil {
+		return err
+	}
 	if err = filter.ID.Add(values); err != nil {
 		return err
 	}
@@ -76,15 +69,15 @@ func (filter *ListProjectFilters) Add(values url.Values) (err error) {
 	if err = filter.Limit.Add(values); err != nil {
 		return err
 	}
-	return nil
+	return filter.Where.Add(values)
 }
 
 // ListProjects returns a list of all buckets created or associated with Forge secrets used for token creation
-func (api HubAPI) ListProjects(hubKey string, filters *ListProjectFilters) (result ForgeResponseArray, err error) {
+func (api HubAPI) ListProjects(hubKey string, filters *ListProjectFilters, opts ...clientapi.CallOption) (result ForgeResponseArray, err error) {
 
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpListProjects, opts...),
+		OpListProjects.Scope,
 		api.Path(hubKey, "projects"),
 		&result,
 		filters,
@@ -92,21 +85,21 @@ func (api HubAPI) ListProjects(hubKey string, filters *ListProjectFilters) (resu
 	return result, err
 }
 
-func (api HubAPI) GetProjectDetails(hubKey, projectKey string) (result ForgeResponseObject, err error) {
+func (api HubAPI) GetProjectDetails(hubKey, projectKey string, opts ...clientapi.CallOption) (result ForgeResponseObject, err error) {
 
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetProjectDetails, opts...),
+		OpGetProjectDetails.Scope,
 		api.Path(hubKey, "projects", projectKey),
 		&result,
 	)
 	return result, err
 }
 
-func (api HubAPI) GetTopFolders(hubKey, projectKey string) (result ForgeResponseArray, err error) {
+func (api HubAPI) GetTopFolders(hubKey, projectKey string, opts ...clientapi.CallOption) (result ForgeResponseArray, err error) {
 	err = api.Client.Get(
-		context.Background(),
-		scopes.DataRead,
+		clientapi.CallContext(OpGetTopFolders, opts...),
+		OpGetTopFolders.Scope,
 		api.Path(hubKey, "projects", projectKey, "topFolders"),
 		&result,
 	)
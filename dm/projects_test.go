@@ -1,7 +1,6 @@
 package dm_test
 
 import (
-	"os"
 	"testing"
 
 	"github.com/gdey/forge-api-go-client/dm"
@@ -11,11 +10,9 @@ import (
 func TestProjectAPI_GetProjects(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
+	hubAPI := dm.HubAPI{Client: env.GetClientTest(t)}
 
-	hubAPI := dm.NewHubAPIWithCredentials(clientID, clientSecret)
-
-	testHubKey := os.Getenv("BIM_360_TEST_ACCOUNT_HUBKEY")
+	testHubKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_HUBKEY")
 
 	t.Run("List all projects under a given hub", func(t *testing.T) {
 		_, err := hubAPI.ListProjects(testHubKey, nil)
@@ -37,12 +34,10 @@ func TestProjectAPI_GetProjects(t *testing.T) {
 func TestProjectAPI_GetProjectDetails(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	hubAPI := dm.NewHubAPIWithCredentials(clientID, clientSecret)
+	hubAPI := dm.HubAPI{Client: env.GetClientTest(t)}
 
-	testHubKey := os.Getenv("BIM_360_TEST_ACCOUNT_HUBKEY")
-	testProjectKey := os.Getenv("BIM_360_TEST_ACCOUNT_PROJECTKEY")
+	testHubKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_HUBKEY")
+	testProjectKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_PROJECTKEY")
 
 	t.Run("List all projects under a given hub", func(t *testing.T) {
 		_, err := hubAPI.GetProjectDetails(testHubKey, testProjectKey)
@@ -64,12 +59,10 @@ func TestProjectAPI_GetProjectDetails(t *testing.T) {
 func TestProjectAPI_GetTopFolders(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-
-	hubAPI := dm.NewHubAPIWithCredentials(clientID, clientSecret)
+	hubAPI := dm.HubAPI{Client: env.GetClientTest(t)}
 
-	testHubKey := os.Getenv("BIM_360_TEST_ACCOUNT_HUBKEY")
-	testProjectKey := os.Getenv("BIM_360_TEST_ACCOUNT_PROJECTKEY")
+	testHubKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_HUBKEY")
+	testProjectKey := env.GetTest(t, "BIM_360_TEST_ACCOUNT_PROJECTKEY")
 
 	t.Run("List all projects under a given hub", func(t *testing.T) {
 		_, err := hubAPI.GetTopFolders(testHubKey, testProjectKey)
@@ -3,4 +3,6 @@
 // The Forge API for Go provides APIs that developers can use to build Go applications
 // that use Autodesk Forge services, such as Reality Capture API (https://developer.autodesk.com/api/reality-capture-cover-page/),
 // Model Derivative API (https://developer.autodesk.com/api/model-derivative-and-viewer-cover-page/) and many others
+//
+// A Session gives access to all the service APIs, sharing their configuration and tokens.
 package forge
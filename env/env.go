@@ -1,21 +1,116 @@
+// Package env provides the credentials and resources of the tests, from the environment
+// or from the cassettes recorded in testdata/cassettes.
+//
+// A test calling GetClientTest replays its cassette, testdata/cassettes/<TestName>.json,
+// when there is one, so it runs offline. Set FORGE_RECORD=1, with the credentials, to run
+// the tests against the service and (re)record their cassettes:
+//
+//	FORGE_RECORD=1 FORGE_CLIENT_ID=... FORGE_CLIENT_SECRET=... go test ./dm ./md ./recap
 package env
 
 import (
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/api/recorder"
+	"github.com/gdey/forge-api-go-client/oauth/twolegged"
+)
+
+// CassetteDir is the directory, relative to the package of the test, of the cassettes
+const CassetteDir = "testdata/cassettes"
+
+// The credentials returned when replaying a cassette
+const (
+	ReplayClientID     = "replay-client-id"
+	ReplayClientSecret = "replay-client-secret"
 )
 
+// recorders are the recorders of the running tests, by test name
+var recorders sync.Map
+
+// Recording returns true if the cassettes are being recorded, see FORGE_RECORD
+func Recording() bool { return os.Getenv("FORGE_RECORD") != "" }
+
+// CassettePath returns the path of the cassette of the test
+func CassettePath(t testing.TB) string {
+	name := strings.NewReplacer("/", "_", " ", "_").Replace(t.Name())
+	return filepath.Join(CassetteDir, name+".json")
+}
+
+// useRecorder registers rec as the recorder of the test until it ends, see HTTPClient
+func useRecorder(t testing.TB, rec *recorder.Recorder) {
+	t.Helper()
+	recorders.Store(t.Name(), rec)
+	t.Cleanup(func() {
+		recorders.Delete(t.Name())
+		if err := rec.Stop(); err != nil {
+			t.Errorf("Failed to save cassette %v: %s\n", rec.Path, err.Error())
+		}
+	})
+}
+
+// recorderOf returns the recorder of the test, or of the test it is a subtest of
+func recorderOf(t testing.TB) (*recorder.Recorder, bool) {
+	name, _, _ := strings.Cut(t.Name(), "/")
+	rec, ok := recorders.Load(name)
+	if !ok {
+		return nil, false
+	}
+	return rec.(*recorder.Recorder), true
+}
+
+// HTTPClient returns an http client sending its requests through the recorder of the
+// test, if it has one. Only the clients of HTTPClient and GetClientTest are recorded.
+func HTTPClient(t testing.TB) *http.Client {
+	if rec, ok := recorderOf(t); ok {
+		return &http.Client{Transport: rec}
+	}
+	return new(http.Client)
+}
+
+// GetClientTest returns a client authenticated with the credentials of GetClientSecretTest.
+// Its requests, and the authentication ones, go through the recorder of the test.
+func GetClientTest(t *testing.T) *api.Client {
+	id, secret := GetClientSecretTest(t)
+	auth := twolegged.NewAuth(id, secret)
+	auth.HTTPClient = HTTPClient(t)
+	client := api.NewClient(auth)
+	client.Client = *HTTPClient(t)
+	return client
+}
+
 // GetClientSecretTest will retrive the ClientID and ClientSecret from the env, or
-// call t.Skip if not found
+// call t.Skip if not found. If the test has a cassette a recorder replays it, and replay
+// credentials are returned.
 func GetClientSecretTest(t *testing.T) (id, secret string) {
 	// prepare the credentials
 	id, secret = os.Getenv("FORGE_CLIENT_ID"), os.Getenv("FORGE_CLIENT_SECRET")
+	path := CassettePath(t)
+	if !Recording() {
+		if _, err := os.Stat(path); err == nil {
+			rec, err := recorder.New(path, recorder.ModeReplay)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+			useRecorder(t, rec)
+			return ReplayClientID, ReplayClientSecret
+		}
+	}
 	if id == "" {
 		t.Skip("ClientID not set")
 	}
 	if secret == "" {
 		t.Skip("ClientSecret not set")
 	}
+	if Recording() {
+		rec, _ := recorder.New(path, recorder.ModeRecord)
+		useRecorder(t, rec)
+	}
 	return id, secret
 }
 
@@ -26,11 +121,27 @@ func GetClientSecret() (id, secret string) {
 }
 
 // GetTest will retrive the named env bar or Skip if
-// the environmental variable is empty.
+// the environmental variable is empty. While a cassette is recorded the value is
+// saved in it, and while it is replayed the value comes from it.
 func GetTest(t *testing.T, name string) (value string) {
+	rec, ok := recorderOf(t)
+	if ok && !Recording() {
+		if value = rec.Cassette().Values[name]; value == "" {
+			t.Skipf("%v not recorded in %v", name, CassettePath(t))
+		}
+		return value
+	}
 	value = os.Getenv(name)
 	if value == "" {
 		t.Skipf("%v not set", name)
 	}
+	if ok {
+		// recording
+		cassette := rec.Cassette()
+		if cassette.Values == nil {
+			cassette.Values = make(map[string]string)
+		}
+		cassette.Values[name] = value
+	}
 	return value
 }
@@ -5,13 +5,14 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 
 	clientapi "github.com/gdey/forge-api-go-client/api"
 	"github.com/gdey/forge-api-go-client/api/filters"
-	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/config"
 	"github.com/gdey/forge-api-go-client/oauth/twolegged"
 )
 
@@ -33,6 +34,8 @@ var (
 
 const (
 	DefaultModelDerivativePath = "/modelderivative/v2/designdata"
+	// DefaultModelDerivativeEMEAPath is the path for the data stored in the EMEA region
+	DefaultModelDerivativeEMEAPath = "/modelderivative/v2/regions/eu/designdata"
 )
 
 //TranslationParams is used when specifying the translation jobs
@@ -161,6 +164,27 @@ func NewAPIWithCredentials(ClientID string, ClientSecret string) ModelDerivative
 	}
 }
 
+// APIPathFor returns the Model Derivative path of cfg, which depends on its region
+func APIPathFor(cfg config.Config) string {
+	switch {
+	case cfg.Paths.ModelDerivative != "":
+		return cfg.Paths.ModelDerivative
+	case cfg.Region.Normalize() == config.RegionEMEA:
+		return DefaultModelDerivativeEMEAPath
+	default:
+		return DefaultModelDerivativePath
+	}
+}
+
+// NewAPIFromConfig returns a Model Derivative API client for the host, region and credentials of cfg
+func NewAPIFromConfig(cfg config.Config) (ModelDerivativeAPI, error) {
+	client, err := cfg.Client(context.Background())
+	if err != nil {
+		return ModelDerivativeAPI{}, err
+	}
+	return ModelDerivativeAPI{Client: client, APIPath: APIPathFor(cfg)}, nil
+}
+
 func (api ModelDerivativeAPI) path(paths ...string) []string {
 	if api.APIPath == "" {
 		return append([]string{DefaultModelDerivativePath}, paths...)
@@ -169,15 +193,15 @@ func (api ModelDerivativeAPI) path(paths ...string) []string {
 }
 
 // TranslateWithParams triggers translation job with settings specified in given TranslationParams
-func (api ModelDerivativeAPI) TranslateWithParams(params TranslationParams) (result TranslationResult, err error) {
+func (api ModelDerivativeAPI) TranslateWithParams(params TranslationParams, opts ...clientapi.CallOption) (result TranslationResult, err error) {
 	byteParams, err := json.Marshal(params)
 	if err != nil {
 		return result, err
 	}
 
 	res, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodPost,
-		scopes.DataRead|scopes.DataWrite,
+		clientapi.CallContext(OpTranslate, opts...), OpTranslate.Method,
+		OpTranslate.Scope,
 		api.path("job"),
 		nil, nil,
 		clientapi.ContentTypeJSON,
@@ -186,22 +210,30 @@ func (api ModelDerivativeAPI) TranslateWithParams(params TranslationParams) (res
 	if err != nil {
 		return result, err
 	}
-	err = api.Client.ProcessRawError(res, &result)
-	return result, err
+	defer res.Body.Close()
+	// a new job is created, an existing one is returned with http.StatusOK
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
+		content, _ := ioutil.ReadAll(res.Body)
+		return result, clientapi.ErrResult{StatusCode: res.StatusCode, Reason: string(content)}
+	}
+	if err = json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("JSON Decode: %w", err)
+	}
+	return result, nil
 }
 
 // TranslateToSVF is a helper function that will use the TranslationSVFPreset for translating into svf a given ObjectID.
 // It will also take care of converting objectID into Base64 (URL Safe) encoded URN.
-func (api ModelDerivativeAPI) TranslateToSVF(objectID string) (result TranslationResult, err error) {
+func (api ModelDerivativeAPI) TranslateToSVF(objectID string, opts ...clientapi.CallOption) (result TranslationResult, err error) {
 	params := TranslationSVFPreset
 	params.Input.URN = base64.RawURLEncoding.EncodeToString([]byte(objectID))
-	return api.TranslateWithParams(params)
+	return api.TranslateWithParams(params, opts...)
 }
 
-func (api ModelDerivativeAPI) GetManifest(urn string) (result ManifestResult, err error) {
+func (api ModelDerivativeAPI) GetManifest(urn string, opts ...clientapi.CallOption) (result ManifestResult, err error) {
 	res, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodGet,
-		scopes.DataRead,
+		clientapi.CallContext(OpGetManifest, opts...), OpGetManifest.Method,
+		OpGetManifest.Scope,
 		api.path(urn, "manifest"),
 		nil, nil,
 		clientapi.ContentTypeJSON,
@@ -214,10 +246,10 @@ func (api ModelDerivativeAPI) GetManifest(urn string) (result ManifestResult, er
 	return result, err
 }
 
-func (api ModelDerivativeAPI) GetMetadata(urn string) (result MetadataResult, err error) {
+func (api ModelDerivativeAPI) GetMetadata(urn string, opts ...clientapi.CallOption) (result MetadataResult, err error) {
 	res, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodGet,
-		scopes.DataRead,
+		clientapi.CallContext(OpGetMetadata, opts...), OpGetMetadata.Method,
+		OpGetMetadata.Scope,
 		api.path(urn, "metadata"),
 		nil, nil,
 		clientapi.ContentTypeJSON,
@@ -230,11 +262,11 @@ func (api ModelDerivativeAPI) GetMetadata(urn string) (result MetadataResult, er
 	return result, err
 }
 
-func (api ModelDerivativeAPI) GetObjectTree(urn string, viewID string) (status int, result TreeResult, err error) {
+func (api ModelDerivativeAPI) GetObjectTree(urn string, viewID string, opts ...clientapi.CallOption) (status int, result TreeResult, err error) {
 
 	res, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodGet,
-		scopes.DataRead,
+		clientapi.CallContext(OpGetObjectTree, opts...), OpGetObjectTree.Method,
+		OpGetObjectTree.Scope,
 		api.path(urn, "metadata", viewID),
 		[]clientapi.Filterer{filters.QueryParam{Key: "forceget", Value: "true"}},
 		nil,
@@ -248,10 +280,10 @@ func (api ModelDerivativeAPI) GetObjectTree(urn string, viewID string) (status i
 	return res.StatusCode, result, err
 }
 
-func (api ModelDerivativeAPI) GetPropertiesStream(urn string, viewID string) (status int, result io.ReadCloser, err error) {
+func (api ModelDerivativeAPI) GetPropertiesStream(urn string, viewID string, opts ...clientapi.CallOption) (status int, result io.ReadCloser, err error) {
 	res, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodGet,
-		scopes.DataRead,
+		clientapi.CallContext(OpGetProperties, opts...), OpGetProperties.Method,
+		OpGetProperties.Scope,
 		api.path(urn, "metadata", viewID, "properties"),
 		[]clientapi.Filterer{filters.QueryParam{Key: "forceget", Value: "true"}},
 		nil,
@@ -264,9 +296,9 @@ func (api ModelDerivativeAPI) GetPropertiesStream(urn string, viewID string) (st
 	return res.StatusCode, res.Body, nil
 }
 
-func (api ModelDerivativeAPI) GetPropertiesObject(urn string, viewID string) (result PropertiesResult, err error) {
+func (api ModelDerivativeAPI) GetPropertiesObject(urn string, viewID string, opts ...clientapi.CallOption) (result PropertiesResult, err error) {
 
-	status, stream, err := api.GetPropertiesStream(urn, viewID)
+	status, stream, err := api.GetPropertiesStream(urn, viewID, opts...)
 	if err != nil {
 		return result, err
 	}
@@ -283,10 +315,10 @@ func (api ModelDerivativeAPI) GetPropertiesObject(urn string, viewID string) (re
 
 }
 
-func (api ModelDerivativeAPI) GetThumbnail(urn string) (reader io.ReadCloser, err error) {
+func (api ModelDerivativeAPI) GetThumbnail(urn string, opts ...clientapi.CallOption) (reader io.ReadCloser, err error) {
 	response, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodGet,
-		scopes.DataRead,
+		clientapi.CallContext(OpGetThumbnail, opts...), OpGetThumbnail.Method,
+		OpGetThumbnail.Scope,
 		api.path(urn, "thumbnail"),
 		nil, nil,
 		clientapi.ContentTypeJSON,
@@ -4,22 +4,27 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
+	"github.com/gdey/forge-api-go-client/api"
 	"github.com/gdey/forge-api-go-client/dm"
 	"github.com/gdey/forge-api-go-client/env"
 	"github.com/gdey/forge-api-go-client/md"
+	"github.com/gdey/forge-api-go-client/oauth/static"
 )
 
 func TestAPI_TranslateToSVF(t *testing.T) {
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-	bucketAPI := dm.NewBucketAPIWithCredentials(clientID, clientSecret)
-	mdAPI := md.NewAPIWithCredentials(clientID, clientSecret)
+	client := env.GetClientTest(t)
+	bucketAPI := dm.BucketAPI{Client: client}
+	mdAPI := md.ModelDerivativeAPI{Client: client}
 
 	tempBucketName := "go_testing_md_bucket"
-	testFilePath := "../assets/HelloWorld.rvt"
+	testFilePath := "../assets/TestFile.txt"
 
 	var testObject dm.ObjectDetails
 
@@ -46,7 +51,7 @@ func TestAPI_TranslateToSVF(t *testing.T) {
 		}
 		defer file.Close()
 
-		testObject, err = bucketAPI.UploadObject(tempBucketName, "temp_file.rvt", file)
+		testObject, err = bucketAPI.UploadObject(tempBucketName, "temp_file.txt", file)
 
 		if err != nil {
 			t.Fatal("Could not upload the test object, got: ", err.Error())
@@ -62,10 +67,10 @@ func TestAPI_TranslateToSVF(t *testing.T) {
 		result, err := mdAPI.TranslateToSVF(testObject.ObjectID)
 
 		if err != nil {
-			t.Error("Could not translate the test object, got: ", err.Error())
+			t.Fatal("Could not translate the test object, got: ", err.Error())
 		}
 
-		if result.Result == "created" {
+		if result.Result != "created" && result.Result != "success" {
 			t.Error("The test object was uploaded, but failed to create the translation job")
 		}
 	})
@@ -130,3 +135,49 @@ func TestAPI_TranslateToSVF2_JSON_Creation(t *testing.T) {
 	}
 
 }
+
+func TestAPI_TranslateWithParams_Status(t *testing.T) {
+	type tcase struct {
+		status int
+		result string
+		err    bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(`{"result":"` + tc.result + `","urn":"dXJu"}`))
+			}))
+			defer server.Close()
+			auth := static.New("token")
+			auth.Host = server.URL
+			mdAPI := md.ModelDerivativeAPI{Client: api.NewClient(auth)}
+
+			result, err := mdAPI.TranslateToSVF("urn")
+			if tc.err {
+				var errResult api.ErrResult
+				if !errors.As(err, &errResult) || errResult.StatusCode != tc.status {
+					t.Fatalf("Expected a %d error, got %v", tc.status, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+			if result.Result != tc.result || result.URN != "dXJu" {
+				t.Errorf("Unexpected result %+v", result)
+			}
+		}
+	}
+
+	tests := map[string]tcase{
+		"created":      {status: http.StatusCreated, result: "created"},
+		"existing job": {status: http.StatusOK, result: "success"},
+		"bad request":  {status: http.StatusBadRequest, err: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+}
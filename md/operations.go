@@ -0,0 +1,31 @@
+package md
+
+import (
+	"net/http"
+
+	clientapi "github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// Operations for the Model Derivative API
+// ref: https://forge.autodesk.com/en/docs/model-derivative/v2/reference/http/
+var (
+	OpTranslate = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "md.jobs.translate", Method: http.MethodPost, Scope: scopes.DataWrite, Idempotent: true,
+	})
+	OpGetManifest = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "md.manifest.get", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetMetadata = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "md.metadata.list", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetObjectTree = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "md.metadata.tree", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetProperties = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "md.metadata.properties", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetThumbnail = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "md.thumbnail.get", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+)
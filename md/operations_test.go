@@ -0,0 +1,28 @@
+package md_test
+
+import (
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api/apitest"
+	"github.com/gdey/forge-api-go-client/md"
+)
+
+func TestOperationScopes(t *testing.T) {
+	server := apitest.NewServer(t)
+	mdAPI := md.ModelDerivativeAPI{Client: server.Client()}
+
+	apitest.TestOperationScopes(t, server, []apitest.OperationCase{
+		{Op: md.OpTranslate, Call: func() error { _, err := mdAPI.TranslateToSVF("urn:object"); return err }},
+		{Op: md.OpGetManifest, Call: func() error { _, err := mdAPI.GetManifest("urn"); return err }},
+		{Op: md.OpGetMetadata, Call: func() error { _, err := mdAPI.GetMetadata("urn"); return err }},
+		{Op: md.OpGetObjectTree, Call: func() error { _, _, err := mdAPI.GetObjectTree("urn", "view"); return err }},
+		{Op: md.OpGetProperties, Call: func() error { _, err := mdAPI.GetPropertiesObject("urn", "view"); return err }},
+		{Op: md.OpGetThumbnail, Call: func() error {
+			reader, err := mdAPI.GetThumbnail("urn")
+			if err == nil {
+				reader.Close()
+			}
+			return err
+		}},
+	})
+}
@@ -0,0 +1,154 @@
+package oauth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+var (
+	// ErrNotJWT is returned when the access token is not a JSON Web Token (g.e. a legacy opaque token)
+	ErrNotJWT = errors.New("access token is not a jwt")
+	// ErrTokenExpired is returned when verified claims are past their expiry time
+	ErrTokenExpired = errors.New("access token expired")
+)
+
+// Claims reflects the claims carried by a Forge access token
+// ref: https://forge.autodesk.com/en/docs/oauth/v2/developers_guide/asymmetric-encryption/
+type Claims struct {
+	Issuer    string       // The issuer of the token
+	Audience  []string     // The audience the token is intended for
+	ClientID  string       // The client ID of the app the token was issued to
+	UserID    string       // The user the token was issued for, empty for 2-legged tokens
+	ID        string       // Unique identifier for the token
+	Scope     scopes.Scope // The granted scopes that are known to this package
+	Scopes    []string     // The granted scopes as listed in the token
+	ExpiresAt time.Time    // When the token expires
+	IssuedAt  time.Time    // When the token was issued, zero if not present
+	NotBefore time.Time    // When the token starts being valid, zero if not present
+}
+
+// Expired returns true if the token is expired at the given time
+func (c *Claims) Expired(now time.Time) bool {
+	return !c.ExpiresAt.IsZero() && !now.Before(c.ExpiresAt)
+}
+
+// Allows checks to see if the granted scopes allow for all scopes in scope
+func (c *Claims) Allows(scope scopes.Scope) bool { return c.Scope.Allows(scope) }
+
+type jwtHeader struct {
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Type      string `json:"typ"`
+}
+
+type rawClaims struct {
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	ClientID  string          `json:"client_id"`
+	UserID    string          `json:"userid"`
+	ID        string          `json:"jti"`
+	Scope     json.RawMessage `json:"scope"`
+	ExpiresAt int64           `json:"exp"`
+	IssuedAt  int64           `json:"iat"`
+	NotBefore int64           `json:"nbf"`
+}
+
+// stringOrSlice decodes a value that may be a single (space separated) string or a list of strings
+func stringOrSlice(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var list []string
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list, nil
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return nil, err
+	}
+	return strings.Fields(str), nil
+}
+
+func unixTime(sec int64) time.Time {
+	if sec == 0 {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+// splitJWT returns the decoded header, the decoded payload and the signature of the token
+func splitJWT(token string) (header jwtHeader, payload []byte, signature []byte, err error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return header, nil, nil, ErrNotJWT
+	}
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return header, nil, nil, ErrNotJWT
+	}
+	if err = json.Unmarshal(headerBytes, &header); err != nil {
+		return header, nil, nil, ErrNotJWT
+	}
+	if payload, err = base64.RawURLEncoding.DecodeString(parts[1]); err != nil {
+		return header, nil, nil, ErrNotJWT
+	}
+	if signature, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return header, nil, nil, ErrNotJWT
+	}
+	return header, payload, signature, nil
+}
+
+func decodeClaims(payload []byte) (*Claims, error) {
+	var raw rawClaims
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("jwt claims: %w", err)
+	}
+	audience, err := stringOrSlice(raw.Audience)
+	if err != nil {
+		return nil, fmt.Errorf("jwt claims aud: %w", err)
+	}
+	granted, err := stringOrSlice(raw.Scope)
+	if err != nil {
+		return nil, fmt.Errorf("jwt claims scope: %w", err)
+	}
+	claims := &Claims{
+		Issuer:    raw.Issuer,
+		Audience:  audience,
+		ClientID:  raw.ClientID,
+		UserID:    raw.UserID,
+		ID:        raw.ID,
+		Scopes:    granted,
+		ExpiresAt: unixTime(raw.ExpiresAt),
+		IssuedAt:  unixTime(raw.IssuedAt),
+		NotBefore: unixTime(raw.NotBefore),
+	}
+	for _, scp := range granted {
+		claims.Scope |= scopes.For(scp)
+	}
+	return claims, nil
+}
+
+// ParseClaims decodes the claims of the access token without verifying its signature.
+// Use VerifyClaims if the token comes from an untrusted source.
+func ParseClaims(token string) (*Claims, error) {
+	_, payload, _, err := splitJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	return decodeClaims(payload)
+}
+
+// Claims decodes the claims of the access token without verifying its signature
+func (b Bearer) Claims() (*Claims, error) { return ParseClaims(b.AccessToken) }
+
+// VerifiedClaims decodes the claims of the access token after checking its signature
+// against the keys provided by source, and that it has not expired.
+func (b Bearer) VerifiedClaims(source KeySource) (*Claims, error) {
+	return VerifyClaims(b.AccessToken, source)
+}
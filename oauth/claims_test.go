@@ -0,0 +1,133 @@
+package oauth_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	encode := func(v interface{}) string {
+		content, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("Failed to marshal: %s\n", err.Error())
+		}
+		return base64.RawURLEncoding.EncodeToString(content)
+	}
+	signed := encode(map[string]string{"alg": "RS256", "kid": kid, "typ": "JWT"}) + "." + encode(claims)
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatalf("Failed to sign: %s\n", err.Error())
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func jwksFor(kid string, key *rsa.PublicKey) oauth.JWKS {
+	return oauth.JWKS{Keys: []oauth.JWK{{
+		KeyID:   kid,
+		KeyType: "RSA",
+		N:       base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:       base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}}}
+}
+
+func TestClaims(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %s\n", err.Error())
+	}
+	keys := jwksFor("key-1", &key.PublicKey)
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	token := signToken(t, key, "key-1", map[string]interface{}{
+		"scope":     []string{"data:read", "bucket:read", "something:new"},
+		"client_id": "the-client",
+		"userid":    "the-user",
+		"aud":       "https://autodesk.com/aud/ajwtexp60",
+		"exp":       expires.Unix(),
+	})
+	bearer := oauth.Bearer{TokenType: "Bearer", AccessToken: token}
+
+	t.Run("Parse without verification", func(t *testing.T) {
+		claims, err := bearer.Claims()
+		if err != nil {
+			t.Fatalf("Failed to parse claims: %s\n", err.Error())
+		}
+		if claims.ClientID != "the-client" || claims.UserID != "the-user" {
+			t.Errorf("Unexpected identities: %v %v", claims.ClientID, claims.UserID)
+		}
+		if claims.Scope != scopes.DataRead|scopes.BucketRead {
+			t.Errorf("Expected scope data:read bucket:read, got %v", claims.Scope)
+		}
+		if len(claims.Scopes) != 3 {
+			t.Errorf("Expected the raw scopes to be kept, got %v", claims.Scopes)
+		}
+		if !claims.ExpiresAt.Equal(expires) {
+			t.Errorf("Expected expiry %v, got %v", expires, claims.ExpiresAt)
+		}
+		if len(claims.Audience) != 1 {
+			t.Errorf("Expected one audience, got %v", claims.Audience)
+		}
+	})
+
+	t.Run("Verify with local key set", func(t *testing.T) {
+		if _, err := bearer.VerifiedClaims(keys); err != nil {
+			t.Fatalf("Failed to verify claims: %s\n", err.Error())
+		}
+	})
+
+	t.Run("Verify with fetched key set", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewEncoder(w).Encode(keys)
+		}))
+		defer server.Close()
+		if _, err := bearer.VerifiedClaims(&oauth.RemoteJWKS{URL: server.URL}); err != nil {
+			t.Fatalf("Failed to verify claims: %s\n", err.Error())
+		}
+	})
+
+	t.Run("Signed by another key", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatalf("Failed to generate key: %s\n", err.Error())
+		}
+		forged := signToken(t, other, "key-1", map[string]interface{}{"exp": expires.Unix()})
+		if _, err = oauth.VerifyClaims(forged, keys); !errors.Is(err, oauth.ErrInvalidSignature) {
+			t.Errorf("Expected ErrInvalidSignature, got %v", err)
+		}
+	})
+
+	t.Run("Unknown key", func(t *testing.T) {
+		unknown := signToken(t, key, "key-2", map[string]interface{}{"exp": expires.Unix()})
+		if _, err := oauth.VerifyClaims(unknown, keys); !errors.Is(err, oauth.ErrUnknownKey) {
+			t.Errorf("Expected ErrUnknownKey, got %v", err)
+		}
+	})
+
+	t.Run("Expired token", func(t *testing.T) {
+		expired := signToken(t, key, "key-1", map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()})
+		if _, err := oauth.VerifyClaims(expired, keys); !errors.Is(err, oauth.ErrTokenExpired) {
+			t.Errorf("Expected ErrTokenExpired, got %v", err)
+		}
+	})
+
+	t.Run("Opaque token", func(t *testing.T) {
+		if _, err := oauth.ParseClaims("not-a-jwt"); !errors.Is(err, oauth.ErrNotJWT) {
+			t.Errorf("Expected ErrNotJWT, got %v", err)
+		}
+	})
+}
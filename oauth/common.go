@@ -1,7 +1,9 @@
 package oauth
 
 import (
+	"encoding/json"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/gdey/forge-api-go-client/oauth/scopes"
@@ -26,10 +28,34 @@ type Bearer struct {
 	RefreshToken string `json:"refresh_token,omitempty"` // The refresh token used in 3-legged oauth
 }
 
+// Redacted is what a Secret is replaced with when printed or marshaled
+const Redacted = "REDACTED"
+
+// Secret is a string that is redacted when printed or marshaled to JSON,
+// so it does not leak into logs or serialized configurations.
+type Secret string
+
+// String returns Redacted for a non empty secret
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// GoString returns the quoted redacted form, so %#v does not leak the secret
+func (s Secret) GoString() string { return strconv.Quote(s.String()) }
+
+// MarshalJSON encodes the redacted form of the secret
+func (s Secret) MarshalJSON() ([]byte, error) { return json.Marshal(s.String()) }
+
+// Reveal returns the actual value of the secret
+func (s Secret) Reveal() string { return string(s) }
+
 // AuthData reflects the data common to 2-legged and 3-legged api calls
 type AuthData struct {
 	ClientID           string `json:"client_id,omitempty"`
-	ClientSecret       string `json:"client_secret,omitempty"`
+	ClientSecret       Secret `json:"client_secret,omitempty"`
 	Host               string `json:"host,omitempty"`
 	AuthenticationPath string `json:"auth_path"`
 }
@@ -38,7 +64,7 @@ type AuthData struct {
 func AuthDataForClient(id, secret string) AuthData {
 	return AuthData{
 		ClientID:     id,
-		ClientSecret: secret,
+		ClientSecret: Secret(secret),
 	}
 }
 
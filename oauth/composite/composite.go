@@ -0,0 +1,115 @@
+// Package composite provides authenticators built out of other authenticators,
+// for endpoints that accept both 2-legged and 3-legged tokens.
+package composite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// ErrNoAuthenticator is returned when no authenticator is able to handle the requested scope
+var ErrNoAuthenticator = errors.New("no authenticator for scope")
+
+// setAuthHeader sets the auth headers into a scratch header, only copying them
+// over on success; so a failed authenticator does not leave partial headers behind.
+func setAuthHeader(ctx context.Context, auth oauth.ForgeAuthenticator, scope scopes.Scope, header http.Header) error {
+	scratch := make(http.Header)
+	if err := oauth.SetAuthHeader(ctx, auth, scope, scratch); err != nil {
+		return err
+	}
+	for key, values := range scratch {
+		header[key] = values
+	}
+	return nil
+}
+
+// Fallback tries each authenticator in order, using the first one able to provide
+// a token for the scope. g.e. Fallback{userToken, appToken} will use the 3-legged
+// user token, falling back to the 2-legged app token.
+type Fallback []oauth.ForgeAuthenticator
+
+// Path returns the path of the first authenticator
+func (chain Fallback) Path(paths ...string) string {
+	if len(chain) == 0 {
+		return oauth.AuthData{}.Path(paths...)
+	}
+	return chain[0].Path(paths...)
+}
+
+func (chain Fallback) SetAuthHeader(scope scopes.Scope, header http.Header) error {
+	return chain.SetAuthHeaderContext(context.Background(), scope, header)
+}
+
+func (chain Fallback) SetAuthHeaderContext(ctx context.Context, scope scopes.Scope, header http.Header) error {
+	if len(chain) == 0 {
+		return fmt.Errorf("%w '%v': empty fallback", ErrNoAuthenticator, scope)
+	}
+	errs := make([]error, 0, len(chain))
+	for _, auth := range chain {
+		err := setAuthHeader(ctx, auth, scope, header)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	return fmt.Errorf("%w '%v': %w", ErrNoAuthenticator, scope, errors.Join(errs...))
+}
+
+// Route sends the requests for scopes allowed by Scope to Auth
+type Route struct {
+	Scope scopes.Scope
+	Auth  oauth.ForgeAuthenticator
+}
+
+// Router picks the authenticator based on the requested scope. The first route
+// that allows all the requested scopes is used, otherwise the Default. g.e.
+//
+//	composite.Router{
+//		Routes: []composite.Route{
+//			{Scope: scopes.Bucket, Auth: appAuth},
+//			{Scope: scopes.Data, Auth: userAuth},
+//		},
+//	}
+type Router struct {
+	Routes []Route
+	// Default is used when no route matches, may be nil
+	Default oauth.ForgeAuthenticator
+}
+
+func (router Router) route(scope scopes.Scope) oauth.ForgeAuthenticator {
+	for _, route := range router.Routes {
+		if route.Scope.Allows(scope) {
+			return route.Auth
+		}
+	}
+	return router.Default
+}
+
+// Path returns the path of the Default authenticator, or of the first route
+func (router Router) Path(paths ...string) string {
+	switch {
+	case router.Default != nil:
+		return router.Default.Path(paths...)
+	case len(router.Routes) != 0:
+		return router.Routes[0].Auth.Path(paths...)
+	default:
+		return oauth.AuthData{}.Path(paths...)
+	}
+}
+
+func (router Router) SetAuthHeader(scope scopes.Scope, header http.Header) error {
+	return router.SetAuthHeaderContext(context.Background(), scope, header)
+}
+
+func (router Router) SetAuthHeaderContext(ctx context.Context, scope scopes.Scope, header http.Header) error {
+	auth := router.route(scope)
+	if auth == nil {
+		return fmt.Errorf("%w '%v'", ErrNoAuthenticator, scope)
+	}
+	return setAuthHeader(ctx, auth, scope, header)
+}
@@ -0,0 +1,120 @@
+package composite_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/composite"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+func tokenFor(auth oauth.ForgeAuthenticator, scope scopes.Scope) (string, error) {
+	header := make(http.Header)
+	err := auth.SetAuthHeader(scope, header)
+	return header.Get(oauth.HeaderAuthorization), err
+}
+
+func TestFallback(t *testing.T) {
+	user := static.New("user-token")
+	user.Scope = scopes.DataRead | scopes.DataWrite
+	app := static.New("app-token")
+	auth := composite.Fallback{user, app}
+
+	t.Run("Use first", func(t *testing.T) {
+		token, err := tokenFor(auth, scopes.DataRead)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if token != "Bearer user-token" {
+			t.Errorf("Expected the user token, got %q", token)
+		}
+	})
+
+	t.Run("Fall back", func(t *testing.T) {
+		token, err := tokenFor(auth, scopes.BucketCreate)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if token != "Bearer app-token" {
+			t.Errorf("Expected the app token, got %q", token)
+		}
+	})
+
+	t.Run("All fail", func(t *testing.T) {
+		_, err := tokenFor(composite.Fallback{user}, scopes.BucketCreate)
+		if !errors.Is(err, composite.ErrNoAuthenticator) {
+			t.Errorf("Expected ErrNoAuthenticator, got %v", err)
+		}
+	})
+}
+
+func TestRouter(t *testing.T) {
+	auth := composite.Router{
+		Routes: []composite.Route{
+			{Scope: scopes.Bucket, Auth: static.New("app-token")},
+			{Scope: scopes.Data, Auth: static.New("user-token")},
+		},
+	}
+
+	tests := []struct {
+		name  string
+		scope scopes.Scope
+		token string
+		err   error
+	}{
+		{name: "bucket scope", scope: scopes.BucketCreate, token: "Bearer app-token"},
+		{name: "data scope", scope: scopes.DataRead | scopes.DataWrite, token: "Bearer user-token"},
+		{name: "mixed scope", scope: scopes.DataRead | scopes.BucketRead, err: composite.ErrNoAuthenticator},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			token, err := tokenFor(auth, tc.scope)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if token != tc.token {
+				t.Errorf("Expected token %q, got %q", tc.token, token)
+			}
+		})
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		auth := auth
+		auth.Default = static.New("default-token")
+		token, err := tokenFor(auth, scopes.DataRead|scopes.BucketRead)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if token != "Bearer default-token" {
+			t.Errorf("Expected the default token, got %q", token)
+		}
+	})
+}
+
+func TestWithClient(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get(oauth.HeaderAuthorization)
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	user := static.New("user-token")
+	user.Host, user.Scope = server.URL, scopes.DataRead
+	app := static.New("app-token")
+	app.Host = server.URL
+
+	client := api.NewClient(composite.Fallback{user, app})
+	if err := client.Get(context.Background(), scopes.BucketRead, []string{"oss", "v2", "buckets"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if authorization != "Bearer app-token" {
+		t.Errorf("Expected the app token to be used, got %q", authorization)
+	}
+}
@@ -0,0 +1,39 @@
+package oauth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// ContextAuthenticator is implemented by authenticators that make use of the
+// context of the request, g.e. to impersonate a user per request.
+type ContextAuthenticator interface {
+	SetAuthHeaderContext(ctx context.Context, scope scopes.Scope, header http.Header) error
+}
+
+// SetAuthHeader will set the auth headers using SetAuthHeaderContext if auth is a
+// ContextAuthenticator, otherwise using SetAuthHeader
+func SetAuthHeader(ctx context.Context, auth ForgeAuthenticator, scope scopes.Scope, header http.Header) error {
+	if cauth, ok := auth.(ContextAuthenticator); ok {
+		return cauth.SetAuthHeaderContext(ctx, scope, header)
+	}
+	return auth.SetAuthHeader(scope, header)
+}
+
+type userIDKey struct{}
+
+// WithUserID returns a copy of ctx carrying the id of the user to act on the behalf of.
+// Authenticators that support impersonation (g.e. 2-legged) will set the x-user-id header
+// for requests made with the returned context; the API methods are made with it by
+// passing api.WithContext(ctx).
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, userID)
+}
+
+// UserIDFrom returns the user id carried by ctx
+func UserIDFrom(ctx context.Context) (userID string, ok bool) {
+	userID, ok = ctx.Value(userIDKey{}).(string)
+	return userID, ok && userID != ""
+}
@@ -0,0 +1,94 @@
+// Package credentials loads the Forge client credentials from the environment,
+// configuration files or mounted secrets.
+//
+// The default chain tries, in order:
+//   - the FORGE_CLIENT_ID and FORGE_CLIENT_SECRET environment variables
+//   - a named profile in ~/.forge/credentials
+//   - a secret mounted (g.e. by Kubernetes) in /var/run/secrets/forge
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+)
+
+// ErrNoCredentials is returned by a provider that did not find any credentials;
+// a Chain will move on to the next provider.
+var ErrNoCredentials = errors.New("no forge credentials found")
+
+// Provider retrieves the credentials used to authenticate with Forge
+type Provider interface {
+	Retrieve(ctx context.Context) (oauth.AuthData, error)
+}
+
+// ProviderFunc is a user supplied function used as a Provider
+type ProviderFunc func(ctx context.Context) (oauth.AuthData, error)
+
+// Retrieve calls fn
+func (fn ProviderFunc) Retrieve(ctx context.Context) (oauth.AuthData, error) { return fn(ctx) }
+
+// Chain is a list of providers that are tried in order
+type Chain []Provider
+
+// DefaultChain returns the environment, profile and mounted secret providers, followed
+// by the given providers
+func DefaultChain(providers ...Provider) Chain {
+	return append(Chain{Env{}, Profile{}, MountedSecret{}}, providers...)
+}
+
+// Retrieve returns the credentials of the first provider that has them. Providers
+// that return ErrNoCredentials are skipped, any other error stops the chain.
+// The Host and AuthenticationPath are set to their defaults if not provided.
+func (chain Chain) Retrieve(ctx context.Context) (data oauth.AuthData, err error) {
+	for _, provider := range chain {
+		data, err = provider.Retrieve(ctx)
+		if errors.Is(err, ErrNoCredentials) {
+			continue
+		}
+		if err != nil {
+			return data, err
+		}
+		if data.ClientID == "" || data.ClientSecret == "" {
+			return data, fmt.Errorf("%T: incomplete credentials, client id and secret are required", provider)
+		}
+		return withDefaults(data), nil
+	}
+	return oauth.AuthData{}, ErrNoCredentials
+}
+
+// Retrieve returns the credentials found by the DefaultChain
+func Retrieve(ctx context.Context) (oauth.AuthData, error) {
+	return DefaultChain().Retrieve(ctx)
+}
+
+func withDefaults(data oauth.AuthData) oauth.AuthData {
+	if data.Host == "" {
+		data.Host = oauth.DefaultHost
+	}
+	data.Host = strings.TrimSuffix(data.Host, "/")
+	if data.AuthenticationPath == "" {
+		data.AuthenticationPath = oauth.DefaultAuthenticationPath
+	}
+	return data
+}
+
+// set assigns the value for the given credentials key; used by the file based providers
+func set(data *oauth.AuthData, key, value string) bool {
+	switch key {
+	case "client_id":
+		data.ClientID = value
+	case "client_secret":
+		data.ClientSecret = oauth.Secret(value)
+	case "host":
+		data.Host = value
+	case "auth_path":
+		data.AuthenticationPath = value
+	default:
+		return false
+	}
+	return true
+}
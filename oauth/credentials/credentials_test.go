@@ -0,0 +1,163 @@
+package credentials_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/credentials"
+)
+
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		credentials.EnvClientID, credentials.EnvClientSecret, credentials.EnvHost,
+		credentials.EnvAuthPath, credentials.EnvProfile, credentials.EnvCredentialsFile,
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %v: %s\n", path, err.Error())
+	}
+}
+
+func TestChain(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	credentialsFile := filepath.Join(dir, "credentials")
+	writeFile(t, credentialsFile, `
+# the default profile
+[default]
+client_id = default-id
+client_secret = default-secret
+
+[profile staging]
+client_id = staging-id
+client_secret = staging-secret
+host = https://staging.example.com/
+`)
+	secretDir := filepath.Join(dir, "secret")
+	if err := os.Mkdir(secretDir, 0700); err != nil {
+		t.Fatalf("Failed to create dir: %s\n", err.Error())
+	}
+	writeFile(t, filepath.Join(secretDir, "client_id"), "mounted-id\n")
+	writeFile(t, filepath.Join(secretDir, "client_secret"), "mounted-secret\n")
+
+	chain := credentials.Chain{
+		credentials.Env{},
+		credentials.Profile{Path: credentialsFile},
+		credentials.MountedSecret{Dir: secretDir},
+	}
+
+	t.Run("Environment first", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv(credentials.EnvClientID, "env-id")
+		t.Setenv(credentials.EnvClientSecret, "env-secret")
+		data, err := chain.Retrieve(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if data.ClientID != "env-id" || data.ClientSecret.Reveal() != "env-secret" {
+			t.Errorf("Expected env credentials, got %v", data)
+		}
+		if data.Host != oauth.DefaultHost || data.AuthenticationPath != oauth.DefaultAuthenticationPath {
+			t.Errorf("Expected default host and auth path, got %v", data)
+		}
+	})
+
+	t.Run("Incomplete environment", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv(credentials.EnvClientID, "env-id")
+		if _, err := chain.Retrieve(ctx); err == nil {
+			t.Errorf("Expected an error for incomplete credentials")
+		}
+	})
+
+	t.Run("Default profile", func(t *testing.T) {
+		clearEnv(t)
+		data, err := chain.Retrieve(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if data.ClientID != "default-id" {
+			t.Errorf("Expected default profile, got %v", data.ClientID)
+		}
+	})
+
+	t.Run("Named profile", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv(credentials.EnvProfile, "staging")
+		data, err := chain.Retrieve(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if data.ClientID != "staging-id" || data.Host != "https://staging.example.com" {
+			t.Errorf("Expected staging profile, got %v", data)
+		}
+	})
+
+	t.Run("Mounted secret", func(t *testing.T) {
+		clearEnv(t)
+		t.Setenv(credentials.EnvProfile, "production")
+		data, err := chain.Retrieve(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if data.ClientID != "mounted-id" || data.ClientSecret.Reveal() != "mounted-secret" {
+			t.Errorf("Expected mounted credentials, got %v", data)
+		}
+	})
+
+	t.Run("User function", func(t *testing.T) {
+		clearEnv(t)
+		chain := credentials.Chain{
+			credentials.Profile{Path: filepath.Join(dir, "missing")},
+			credentials.ProviderFunc(func(context.Context) (oauth.AuthData, error) {
+				return oauth.AuthDataForClient("func-id", "func-secret"), nil
+			}),
+		}
+		data, err := chain.Retrieve(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if data.ClientID != "func-id" {
+			t.Errorf("Expected credentials from the function, got %v", data.ClientID)
+		}
+	})
+
+	t.Run("Nothing found", func(t *testing.T) {
+		clearEnv(t)
+		chain := credentials.Chain{credentials.Env{}, credentials.MountedSecret{Dir: filepath.Join(dir, "missing")}}
+		if _, err := chain.Retrieve(ctx); !errors.Is(err, credentials.ErrNoCredentials) {
+			t.Errorf("Expected ErrNoCredentials, got %v", err)
+		}
+	})
+}
+
+func TestSecretRedaction(t *testing.T) {
+	data := oauth.AuthDataForClient("the-id", "the-secret")
+
+	content, err := json.Marshal(data)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %s\n", err.Error())
+	}
+	for _, str := range []string{string(content), fmt.Sprintf("%v", data), fmt.Sprintf("%+v", data), fmt.Sprintf("%#v", data)} {
+		if strings.Contains(str, "the-secret") {
+			t.Errorf("Secret leaked in %s", str)
+		}
+		if !strings.Contains(str, "the-id") {
+			t.Errorf("Expected client id in %s", str)
+		}
+	}
+}
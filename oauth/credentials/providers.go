@@ -0,0 +1,179 @@
+package credentials
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+)
+
+const (
+	// EnvClientID is the environment variable holding the client id
+	EnvClientID = "FORGE_CLIENT_ID"
+	// EnvClientSecret is the environment variable holding the client secret
+	EnvClientSecret = "FORGE_CLIENT_SECRET"
+	// EnvHost is the environment variable holding the host
+	EnvHost = "FORGE_HOST"
+	// EnvAuthPath is the environment variable holding the authentication path
+	EnvAuthPath = "FORGE_AUTH_PATH"
+	// EnvProfile is the environment variable naming the profile to use
+	EnvProfile = "FORGE_PROFILE"
+	// EnvCredentialsFile is the environment variable overriding the credentials file location
+	EnvCredentialsFile = "FORGE_CREDENTIALS_FILE"
+
+	// DefaultProfile is the profile used when none is named
+	DefaultProfile = "default"
+	// DefaultSecretDir is where the MountedSecret provider looks for the secret files
+	DefaultSecretDir = "/var/run/secrets/forge"
+)
+
+// Env retrieves the credentials from the FORGE_CLIENT_ID, FORGE_CLIENT_SECRET,
+// FORGE_HOST and FORGE_AUTH_PATH environment variables
+type Env struct{}
+
+func (Env) Retrieve(context.Context) (data oauth.AuthData, err error) {
+	data = oauth.AuthData{
+		ClientID:           os.Getenv(EnvClientID),
+		ClientSecret:       oauth.Secret(os.Getenv(EnvClientSecret)),
+		Host:               os.Getenv(EnvHost),
+		AuthenticationPath: os.Getenv(EnvAuthPath),
+	}
+	if data.ClientID == "" && data.ClientSecret == "" {
+		return data, ErrNoCredentials
+	}
+	return data, nil
+}
+
+// Profile retrieves the credentials from a named profile in an ini style credentials file:
+//
+//	[default]
+//	client_id = ...
+//	client_secret = ...
+//	host = https://developer.api.autodesk.com
+//
+//	[staging]
+//	client_id = ...
+type Profile struct {
+	// Path of the credentials file. If empty FORGE_CREDENTIALS_FILE is used, falling
+	// back to ~/.forge/credentials
+	Path string
+	// Name of the profile. If empty FORGE_PROFILE is used, falling back to DefaultProfile
+	Name string
+}
+
+func (p Profile) path() (string, error) {
+	if p.Path != "" {
+		return p.Path, nil
+	}
+	if path := os.Getenv(EnvCredentialsFile); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", ErrNoCredentials
+	}
+	return filepath.Join(home, ".forge", "credentials"), nil
+}
+
+func (p Profile) name() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	if name := os.Getenv(EnvProfile); name != "" {
+		return name
+	}
+	return DefaultProfile
+}
+
+func (p Profile) Retrieve(context.Context) (data oauth.AuthData, err error) {
+	path, err := p.path()
+	if err != nil {
+		return data, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return data, ErrNoCredentials
+	}
+	if err != nil {
+		return data, err
+	}
+	defer file.Close()
+
+	var (
+		name    = p.name()
+		section string
+		found   bool
+		lineNo  int
+		scanner = bufio.NewScanner(file)
+	)
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return data, fmt.Errorf("%v:%d: malformed section %q", path, lineNo, line)
+			}
+			section = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line[1:len(line)-1]), "profile "))
+			found = found || section == name
+			continue
+		}
+		if section != name {
+			continue
+		}
+		idx := strings.IndexByte(line, '=')
+		if idx == -1 {
+			return data, fmt.Errorf("%v:%d: expected key = value", path, lineNo)
+		}
+		key, value := strings.TrimSpace(line[:idx]), strings.TrimSpace(line[idx+1:])
+		if !set(&data, strings.ToLower(key), value) {
+			return data, fmt.Errorf("%v:%d: unknown key %q", path, lineNo, key)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return data, err
+	}
+	if !found {
+		return data, fmt.Errorf("profile %q not in %v: %w", name, path, ErrNoCredentials)
+	}
+	return data, nil
+}
+
+// MountedSecret retrieves the credentials from a directory holding one file per
+// value (client_id, client_secret, host and auth_path), as created when mounting a
+// Kubernetes secret as a volume.
+type MountedSecret struct {
+	// Dir holding the files, if empty DefaultSecretDir is used
+	Dir string
+}
+
+func (m MountedSecret) Retrieve(context.Context) (data oauth.AuthData, err error) {
+	dir := m.Dir
+	if dir == "" {
+		dir = DefaultSecretDir
+	}
+	var found bool
+	for _, key := range [...]string{"client_id", "client_secret", "host", "auth_path"} {
+		content, err := os.ReadFile(filepath.Join(dir, key))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return data, err
+		}
+		set(&data, key, strings.TrimSpace(string(content)))
+		found = true
+	}
+	if !found {
+		return data, ErrNoCredentials
+	}
+	return data, nil
+}
@@ -0,0 +1,133 @@
+package oauth
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// TokenEventKind is the kind of change in the life of a token
+type TokenEventKind uint8
+
+const (
+	// TokenAcquired is emitted when a token is obtained for the first time
+	TokenAcquired TokenEventKind = iota + 1
+	// TokenRefreshed is emitted when a token is replaced by a new one
+	TokenRefreshed
+	// TokenRefreshFailed is emitted when getting a new token failed, the error is in TokenEvent.Err
+	TokenRefreshFailed
+	// TokenExpired is emitted when a token is found to have expired before being replaced
+	TokenExpired
+)
+
+func (k TokenEventKind) String() string {
+	switch k {
+	case TokenAcquired:
+		return "acquired"
+	case TokenRefreshed:
+		return "refreshed"
+	case TokenRefreshFailed:
+		return "refresh failed"
+	case TokenExpired:
+		return "expired"
+	default:
+		return "unknown"
+	}
+}
+
+// TokenEvent describes a change in the life of a token. The token itself is
+// never part of the event, so events can be logged safely.
+type TokenEvent struct {
+	Kind     TokenEventKind
+	ClientID string
+	Scope    scopes.Scope
+	// ExpiresAt is when the token expires, zero for TokenRefreshFailed
+	ExpiresAt time.Time
+	// Err is the reason of a TokenRefreshFailed
+	Err  error
+	Time time.Time
+}
+
+// TokenObserver is called for every TokenEvent of an authenticator. It is called
+// synchronously on the path that got the token, so it should not block.
+type TokenObserver func(event TokenEvent)
+
+// Emit calls the observer, if it is not nil, setting the time of the event
+func (observer TokenObserver) Emit(event TokenEvent) {
+	if observer == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	observer(event)
+}
+
+const (
+	// DefaultRefreshAhead is how long before its expiry a token is refreshed by a Refresher
+	DefaultRefreshAhead = 5 * time.Minute
+	// DefaultRefreshRetry is how long a Refresher waits before retrying a failed refresh
+	DefaultRefreshRetry = 10 * time.Second
+)
+
+// Refresher keeps a token refreshed ahead of its expiry, so requests do not have
+// to wait on a refresh.
+type Refresher struct {
+	// Ahead is how long before the expiry the token is refreshed, if 0 DefaultRefreshAhead is used
+	Ahead time.Duration
+	// Jitter is the maximum random duration the refresh is brought forward by, so
+	// many processes sharing credentials do not refresh at the same time.
+	Jitter time.Duration
+	// Retry is how long to wait before retrying a failed refresh, if 0 DefaultRefreshRetry is used
+	Retry time.Duration
+}
+
+// next returns how long to wait before refreshing a token expiring at expiresAt
+func (r Refresher) next(expiresAt time.Time) time.Duration {
+	ahead := r.Ahead
+	if ahead == 0 {
+		ahead = DefaultRefreshAhead
+	}
+	left := time.Until(expiresAt)
+	if left <= 0 {
+		return 0
+	}
+	wait := left - ahead
+	if r.Jitter > 0 {
+		wait -= time.Duration(rand.Int63n(int64(r.Jitter)))
+	}
+	if wait <= 0 {
+		// tokens living less than ahead are refreshed half way through,
+		// instead of over and over again
+		wait = left / 2
+	}
+	return wait
+}
+
+// Run calls refresh ahead of expiresAt, and then ahead of the expiry returned by each
+// refresh, until ctx is done; a zero expiresAt refreshes straight away. Failed refreshes
+// are retried after Retry. Run blocks, returning ctx.Err(), so it is usually started with
+//
+//	go refresher.Run(ctx, expiresAt, refresh)
+func (r Refresher) Run(ctx context.Context, expiresAt time.Time, refresh func() (time.Time, error)) error {
+	retry := r.Retry
+	if retry == 0 {
+		retry = DefaultRefreshRetry
+	}
+	timer := time.NewTimer(r.next(expiresAt))
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+		wait := retry
+		if next, err := refresh(); err == nil {
+			wait = r.next(next)
+		}
+		timer.Reset(wait)
+	}
+}
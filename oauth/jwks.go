@@ -0,0 +1,206 @@
+package oauth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha256" // register the hashes used by RS256 and friends
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultJWKSURL is where Forge publishes the keys used to sign access tokens
+	DefaultJWKSURL = DefaultHost + "/authentication/v2/keys"
+	// DefaultJWKSTTL is how long fetched keys are kept before being fetched again
+	DefaultJWKSTTL = time.Hour
+)
+
+var (
+	// ErrInvalidSignature is returned when the signature of the token does not match
+	ErrInvalidSignature = errors.New("invalid token signature")
+	// ErrUnknownKey is returned when the token was signed with a key not in the key set
+	ErrUnknownKey = errors.New("unknown signing key")
+)
+
+var signingHashes = map[string]crypto.Hash{
+	"RS256": crypto.SHA256,
+	"RS384": crypto.SHA384,
+	"RS512": crypto.SHA512,
+}
+
+// KeySource provides the public keys used to verify the signature of tokens
+type KeySource interface {
+	PublicKey(kid string) (*rsa.PublicKey, error)
+}
+
+// JWK is a JSON Web Key as published in a JWKS document
+type JWK struct {
+	KeyID     string `json:"kid"`
+	KeyType   string `json:"kty"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+	N         string `json:"n"`
+	E         string `json:"e"`
+}
+
+// RSAPublicKey returns the rsa public key described by the JWK
+func (key JWK) RSAPublicKey() (*rsa.PublicKey, error) {
+	if key.KeyType != "RSA" {
+		return nil, fmt.Errorf("jwk %v: unsupported key type %q", key.KeyID, key.KeyType)
+	}
+	n, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(key.N, "="))
+	if err != nil {
+		return nil, fmt.Errorf("jwk %v: modulus: %w", key.KeyID, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(key.E, "="))
+	if err != nil {
+		return nil, fmt.Errorf("jwk %v: exponent: %w", key.KeyID, err)
+	}
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("jwk %v: exponent too large", key.KeyID)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}, nil
+}
+
+// JWKS is a JSON Web Key Set document
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// ParseJWKS decodes a JWKS document, g.e. one stored locally
+func ParseJWKS(content []byte) (keys JWKS, err error) {
+	err = json.Unmarshal(content, &keys)
+	return keys, err
+}
+
+// FetchJWKS retrieves the JWKS document at url; if client is nil http.DefaultClient is used
+func FetchJWKS(ctx context.Context, client *http.Client, url string) (keys JWKS, err error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return keys, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return keys, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return keys, fmt.Errorf("fetching jwks %v: unexpected status %v", url, res.StatusCode)
+	}
+	err = json.NewDecoder(res.Body).Decode(&keys)
+	return keys, err
+}
+
+// PublicKey returns the key with the given key id
+func (keys JWKS) PublicKey(kid string) (*rsa.PublicKey, error) {
+	for _, key := range keys.Keys {
+		if key.KeyID == kid {
+			return key.RSAPublicKey()
+		}
+	}
+	return nil, ErrUnknownKey
+}
+
+// RemoteJWKS is a KeySource that fetches, and caches, the JWKS document from URL.
+// The document is fetched again when the TTL passed or an unknown key id is seen.
+type RemoteJWKS struct {
+	// URL of the JWKS document, if empty DefaultJWKSURL is used
+	URL string
+	// Client used to fetch the document, if nil http.DefaultClient is used
+	Client *http.Client
+	// TTL is how long the document is cached, if 0 DefaultJWKSTTL is used
+	TTL time.Duration
+
+	mutex   sync.Mutex
+	keys    JWKS
+	fetched time.Time
+}
+
+func (r *RemoteJWKS) fetch() error {
+	url := r.URL
+	if url == "" {
+		url = DefaultJWKSURL
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	keys, err := FetchJWKS(ctx, r.Client, url)
+	if err != nil {
+		return err
+	}
+	r.keys, r.fetched = keys, time.Now()
+	return nil
+}
+
+// PublicKey returns the key with the given key id, fetching the document if needed
+func (r *RemoteJWKS) PublicKey(kid string) (*rsa.PublicKey, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	ttl := r.TTL
+	if ttl == 0 {
+		ttl = DefaultJWKSTTL
+	}
+	if r.fetched.IsZero() || time.Since(r.fetched) >= ttl {
+		if err := r.fetch(); err != nil {
+			return nil, err
+		}
+	}
+	key, err := r.keys.PublicKey(kid)
+	if errors.Is(err, ErrUnknownKey) && time.Since(r.fetched) > time.Minute {
+		// the keys may have been rotated
+		if err = r.fetch(); err != nil {
+			return nil, err
+		}
+		return r.keys.PublicKey(kid)
+	}
+	return key, err
+}
+
+// VerifyClaims checks the signature of the token against the keys provided by source, and that
+// the token is currently valid, before returning its claims.
+func VerifyClaims(token string, source KeySource) (*Claims, error) {
+	header, payload, signature, err := splitJWT(token)
+	if err != nil {
+		return nil, err
+	}
+	hash, ok := signingHashes[header.Algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", header.Algorithm)
+	}
+	key, err := source.PublicKey(header.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	hasher := hash.New()
+	hasher.Write([]byte(token[:strings.LastIndexByte(token, '.')]))
+	if err = rsa.VerifyPKCS1v15(key, hash, hasher.Sum(nil), signature); err != nil {
+		return nil, ErrInvalidSignature
+	}
+	claims, err := decodeClaims(payload)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if claims.Expired(now) {
+		return claims, ErrTokenExpired
+	}
+	if !claims.NotBefore.IsZero() && now.Before(claims.NotBefore) {
+		return claims, fmt.Errorf("access token not valid before %v", claims.NotBefore)
+	}
+	return claims, nil
+}
@@ -1,6 +1,7 @@
 package scopes
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -55,9 +56,25 @@ const (
 	// AccountWrite manage your product and service accounts
 	AccountWrite
 
+	// OpenID allows the app to use OpenID Connect, g.e. the userinfo endpoint
+	OpenID
+
+	// DataSearchRead view the results of searches across your data
+	DataSearchRead
+
+	// DataSearchWrite run and manage searches across your data
+	DataSearchWrite
+
 	scopeEnd // should be last element
 )
 
+const (
+	// Bucket is all of the bucket:* scopes
+	Bucket = BucketCreate | BucketRead | BucketUpdate | BucketDelete
+	// Data is all of the data:* scopes
+	Data = DataRead | DataWrite | DataCreate | DataSearch | DataSearchRead | DataSearchWrite
+)
+
 var scopes = [...]string{
 	"user-profile:read",
 	"user:read",
@@ -74,6 +91,20 @@ var scopes = [...]string{
 	"code:all",
 	"account:read",
 	"account:write",
+	"openid",
+	"data:search:read",
+	"data:search:write",
+}
+
+// implies lists for a scope the scopes it grants as well
+var implies = map[Scope]Scope{
+	DataRead:     ViewablesRead,
+	DataWrite:    DataRead | DataCreate,
+	UserWrite:    UserRead,
+	AccountWrite: AccountRead,
+	// data:search grants all the search variants
+	DataSearch:      DataSearchRead | DataSearchWrite,
+	DataSearchWrite: DataSearchRead,
 }
 
 var invalidMask Scope
@@ -95,51 +126,135 @@ func init() {
 	}
 }
 
-// For takes a space seprated list of scopes and returns the Scope
-// set for it.
-func For(val string) Scope {
-	var scope Scope
-	scps := strings.Split(" ", strings.ToLower(val))
-	if len(scps) == 0 {
-		return scope
+// ErrUnknownScopes is returned when parsing scopes this package does not know about
+type ErrUnknownScopes []string
+
+func (err ErrUnknownScopes) Error() string {
+	return fmt.Sprintf("unknown scopes: %v", strings.Join(err, ", "))
+}
+
+// lookup returns the Scope for a single scope name
+func lookup(name string) (Scope, bool) {
+	for i, scp := range scopes {
+		if scp == name {
+			return Scope(1 << i), true
+		}
 	}
-	for i := range scps {
-		s := strings.TrimSpace(scps[i])
-		for j, scp := range scopes {
-			if scp != s {
-				continue
-			}
-			scope |= Scope(1 << j)
-			break
+	return 0, false
+}
+
+// Parse takes a whitespace separated list of scopes and returns the Scope
+// set for it. The returned error will be of type ErrUnknownScopes, if
+// any of the scopes are not known; the known scopes are still returned.
+func Parse(val string) (Scope, error) {
+	var (
+		scope   Scope
+		unknown ErrUnknownScopes
+	)
+	for _, name := range strings.Fields(strings.ToLower(val)) {
+		scp, ok := lookup(name)
+		if !ok {
+			unknown = append(unknown, name)
+			continue
 		}
+		scope |= scp
+	}
+	if len(unknown) != 0 {
+		return scope, unknown
 	}
+	return scope, nil
+}
+
+// For takes a whitespace separated list of scopes and returns the Scope
+// set for it. Unknown scopes are ignored, use Parse to have them reported.
+func For(val string) Scope {
+	scope, _ := Parse(val)
 	return scope
+}
+
+// Implied returns s along with all the scopes that are granted by the scopes in s
+func (s Scope) Implied() Scope {
+	for {
+		expanded := s
+		for scp, implied := range implies {
+			if s&scp == scp {
+				expanded |= implied
+			}
+		}
+		if expanded == s {
+			return s
+		}
+		s = expanded
+	}
+}
 
+// Minimal returns s without the scopes that are already granted by other scopes in s
+func (s Scope) Minimal() Scope {
+	minimal := s
+	for scp := range implies {
+		if s&scp == 0 {
+			continue
+		}
+		// only drop what another scope grants, never the scope itself
+		minimal &^= scp.Implied() &^ scp
+	}
+	return minimal
 }
 
-// Allows checks to see if s allows for all scopes in s1
-func (s Scope) Allows(s1 Scope) bool { return s&s1 == s1 }
+// Allows checks to see if s allows for all scopes in s1, taking implied scopes into account
+func (s Scope) Allows(s1 Scope) bool { return s.Implied()&s1 == s1 }
 
 // IsValid checks to see if at least one known scope is encoded
 func (s Scope) IsValid() bool { return s != 0 && s&invalidMask == 0 }
 
-// String will return the string version of the set of scopes
-func (s Scope) String() string {
-	if s == 0 {
-		// no scopes defined
-		return ""
-	}
-	var str strings.Builder
-	var first = true
+// Names returns the name of each scope in s
+func (s Scope) Names() []string {
+	var names []string
 	for i := 0; i < len(scopes); i++ {
 		if ((1 << i) & s) == 0 {
 			continue
 		}
-		if !first {
-			str.WriteRune(' ')
-		}
-		str.WriteString(scopes[i])
-		first = false
+		names = append(names, scopes[i])
+	}
+	return names
+}
+
+// String will return the string version of the set of scopes
+func (s Scope) String() string { return strings.Join(s.Names(), " ") }
+
+// MarshalText encodes the scopes as a space separated list
+func (s Scope) MarshalText() ([]byte, error) {
+	if s&invalidMask != 0 {
+		return nil, fmt.Errorf("invalid scope %#x", uint64(s))
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a space separated list of scopes; unknown scopes are an error
+func (s *Scope) UnmarshalText(text []byte) (err error) {
+	*s, err = Parse(string(text))
+	return err
+}
+
+// MarshalJSON encodes the scopes as a space separated string
+func (s Scope) MarshalJSON() ([]byte, error) {
+	text, err := s.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON decodes the scopes from either a space separated string or
+// a list of strings
+func (s *Scope) UnmarshalJSON(content []byte) error {
+	var list []string
+	if err := json.Unmarshal(content, &list); err == nil {
+		return s.UnmarshalText([]byte(strings.Join(list, " ")))
+	}
+	var str string
+	if err := json.Unmarshal(content, &str); err != nil {
+		return fmt.Errorf("scopes: expected string or list of strings: %w", err)
 	}
-	return str.String()
+	return s.UnmarshalText([]byte(str))
 }
@@ -0,0 +1,130 @@
+package scopes_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		val     string
+		scope   scopes.Scope
+		unknown []string
+	}{
+		{name: "empty", val: ""},
+		{name: "single", val: "data:read", scope: scopes.DataRead},
+		{name: "multiple", val: "data:read  bucket:create\topenid", scope: scopes.DataRead | scopes.BucketCreate | scopes.OpenID},
+		{name: "surrounding whitespace", val: " \ndata:read\n ", scope: scopes.DataRead},
+		{name: "only whitespace", val: " \t "},
+		{name: "case insensitive", val: "Data:Write", scope: scopes.DataWrite},
+		{name: "search variants", val: "data:search data:search:read data:search:write", scope: scopes.DataSearch | scopes.DataSearchRead | scopes.DataSearchWrite},
+		{name: "unknown", val: "data:read data:destroy", scope: scopes.DataRead, unknown: []string{"data:destroy"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			scope, err := scopes.Parse(tc.val)
+			if scope != tc.scope {
+				t.Errorf("Expected scope %q, got %q", tc.scope, scope)
+			}
+			var unknown scopes.ErrUnknownScopes
+			switch {
+			case tc.unknown == nil && err != nil:
+				t.Errorf("Unexpected error: %v", err)
+			case tc.unknown != nil && !errors.As(err, &unknown):
+				t.Errorf("Expected ErrUnknownScopes, got %v", err)
+			case tc.unknown != nil && len(unknown) != len(tc.unknown):
+				t.Errorf("Expected unknown scopes %v, got %v", tc.unknown, unknown)
+			}
+			if got := scopes.For(tc.val); got != tc.scope {
+				t.Errorf("Expected For to return %q, got %q", tc.scope, got)
+			}
+		})
+	}
+}
+
+func TestScope_Allows(t *testing.T) {
+	tests := []struct {
+		name    string
+		have    scopes.Scope
+		want    scopes.Scope
+		allowed bool
+	}{
+		{name: "same", have: scopes.DataRead, want: scopes.DataRead, allowed: true},
+		{name: "write implies read", have: scopes.DataWrite, want: scopes.DataRead, allowed: true},
+		{name: "write implies transitive", have: scopes.DataWrite, want: scopes.ViewablesRead, allowed: true},
+		{name: "read does not imply write", have: scopes.DataRead, want: scopes.DataWrite},
+		{name: "partial", have: scopes.DataWrite, want: scopes.DataRead | scopes.BucketRead},
+		{name: "search implies variants", have: scopes.DataSearch, want: scopes.DataSearchRead | scopes.DataSearchWrite, allowed: true},
+		{name: "search write implies read", have: scopes.DataSearchWrite, want: scopes.DataSearchRead, allowed: true},
+		{name: "search read does not imply write", have: scopes.DataSearchRead, want: scopes.DataSearchWrite},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.have.Allows(tc.want); got != tc.allowed {
+				t.Errorf("Expected %q allows %q to be %v", tc.have, tc.want, tc.allowed)
+			}
+		})
+	}
+}
+
+func TestScope_String(t *testing.T) {
+	scope := scopes.DataSearchWrite | scopes.DataSearchRead | scopes.OpenID
+	if got := scope.String(); got != "openid data:search:read data:search:write" {
+		t.Errorf("Unexpected string %q", got)
+	}
+	if parsed, err := scopes.Parse(scope.String()); err != nil || parsed != scope {
+		t.Errorf("Expected %q to parse back, got %q, %v", scope, parsed, err)
+	}
+}
+
+func TestScope_Minimal(t *testing.T) {
+	scope := scopes.DataRead | scopes.DataWrite | scopes.ViewablesRead | scopes.BucketRead
+	want := scopes.DataWrite | scopes.BucketRead
+	if got := scope.Minimal(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestScope_JSON(t *testing.T) {
+	type config struct {
+		Scope scopes.Scope `json:"scope"`
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		content, err := json.Marshal(config{Scope: scopes.DataRead | scopes.BucketCreate})
+		if err != nil {
+			t.Fatalf("Failed to marshal: %s\n", err.Error())
+		}
+		if string(content) != `{"scope":"data:read bucket:create"}` {
+			t.Errorf("Unexpected encoding: %s", content)
+		}
+		var cfg config
+		if err = json.Unmarshal(content, &cfg); err != nil {
+			t.Fatalf("Failed to unmarshal: %s\n", err.Error())
+		}
+		if cfg.Scope != scopes.DataRead|scopes.BucketCreate {
+			t.Errorf("Unexpected scope: %q", cfg.Scope)
+		}
+	})
+
+	t.Run("List of strings", func(t *testing.T) {
+		var cfg config
+		if err := json.Unmarshal([]byte(`{"scope":["data:read","openid"]}`), &cfg); err != nil {
+			t.Fatalf("Failed to unmarshal: %s\n", err.Error())
+		}
+		if cfg.Scope != scopes.DataRead|scopes.OpenID {
+			t.Errorf("Unexpected scope: %q", cfg.Scope)
+		}
+	})
+
+	t.Run("Unknown scope", func(t *testing.T) {
+		var cfg config
+		if err := json.Unmarshal([]byte(`{"scope":"data:read data:destroy"}`), &cfg); err == nil {
+			t.Errorf("Expected an error for unknown scope")
+		}
+	})
+}
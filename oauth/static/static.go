@@ -0,0 +1,63 @@
+// Package static provides an authenticator that always uses the same token,
+// useful for tests and for tokens obtained out of band.
+package static
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// Auth is a ForgeAuthenticator that uses a fixed token
+type Auth struct {
+	oauth.AuthData
+	Bearer oauth.Bearer
+	// Scope, if not 0, is the set of scopes the token was granted; requests
+	// for any other scope will fail.
+	Scope scopes.Scope
+	// UserID is the user to act on the behalf of; it is overridden by
+	// a user id set with oauth.WithUserID on the request context.
+	UserID string
+}
+
+// New returns an authenticator that uses the given access token for all scopes
+func New(accessToken string) Auth {
+	return Auth{
+		Bearer: oauth.Bearer{
+			TokenType:   "Bearer",
+			AccessToken: accessToken,
+		},
+	}
+}
+
+// GetTokenWithScope returns the token if it was granted the given scope
+func (a Auth) GetTokenWithScope(scope scopes.Scope) (*oauth.Bearer, error) {
+	if a.Scope != 0 && !a.Scope.Allows(scope) {
+		return nil, fmt.Errorf("scopes require: '%v' have '%v'", scope, a.Scope)
+	}
+	bearer := a.Bearer
+	return &bearer, nil
+}
+
+func (a Auth) SetAuthHeader(scope scopes.Scope, header http.Header) error {
+	return a.SetAuthHeaderContext(context.Background(), scope, header)
+}
+
+func (a Auth) SetAuthHeaderContext(ctx context.Context, scope scopes.Scope, header http.Header) error {
+	bearer, err := a.GetTokenWithScope(scope)
+	if err != nil {
+		return err
+	}
+	header.Set(oauth.HeaderAuthorization, "Bearer "+bearer.AccessToken)
+	userID := a.UserID
+	if id, ok := oauth.UserIDFrom(ctx); ok {
+		userID = id
+	}
+	if userID != "" {
+		header.Set(oauth.HeaderXUserID, userID)
+	}
+	return nil
+}
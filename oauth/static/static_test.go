@@ -0,0 +1,86 @@
+package static_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/static"
+)
+
+func TestAuth_SetAuthHeader(t *testing.T) {
+	type tcase struct {
+		auth   static.Auth
+		ctx    context.Context
+		scope  scopes.Scope
+		token  string
+		userID string
+		err    bool
+	}
+
+	fn := func(tc tcase) func(*testing.T) {
+		return func(t *testing.T) {
+			header := make(http.Header)
+			ctx := tc.ctx
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			err := tc.auth.SetAuthHeaderContext(ctx, tc.scope, header)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("Expected an error, got header %v", header)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s\n", err.Error())
+			}
+			if got := header.Get(oauth.HeaderAuthorization); got != "Bearer "+tc.token {
+				t.Errorf("Expected authorization Bearer %v, got %v", tc.token, got)
+			}
+			if got := header.Get(oauth.HeaderXUserID); got != tc.userID {
+				t.Errorf("Expected user id %q, got %q", tc.userID, got)
+			}
+		}
+	}
+
+	withUser := static.New("token")
+	withUser.UserID = "user-1"
+	scoped := static.New("token")
+	scoped.Scope = scopes.DataWrite
+
+	tests := map[string]tcase{
+		"any scope":         {auth: static.New("token"), scope: scopes.BucketCreate, token: "token"},
+		"user id":           {auth: withUser, scope: scopes.DataRead, token: "token", userID: "user-1"},
+		"context user id":   {auth: withUser, ctx: oauth.WithUserID(context.Background(), "user-2"), scope: scopes.DataRead, token: "token", userID: "user-2"},
+		"implied scope":     {auth: scoped, scope: scopes.DataRead, token: "token"},
+		"scope not granted": {auth: scoped, scope: scopes.BucketCreate, err: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, fn(tc))
+	}
+
+	t.Run("without context", func(t *testing.T) {
+		header := make(http.Header)
+		if err := withUser.SetAuthHeader(scopes.DataRead, header); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if header.Get(oauth.HeaderAuthorization) != "Bearer token" || header.Get(oauth.HeaderXUserID) != "user-1" {
+			t.Errorf("Unexpected header %v", header)
+		}
+	})
+}
+
+func TestAuth_Path(t *testing.T) {
+	auth := static.New("token")
+	if got := auth.Path("oss", "v2", "buckets"); got != oauth.DefaultHost+"/oss/v2/buckets" {
+		t.Errorf("Unexpected path %v", got)
+	}
+	auth.Host = "http://localhost:8080"
+	if got := auth.Path("oss", "v2", "buckets"); got != "http://localhost:8080/oss/v2/buckets" {
+		t.Errorf("Unexpected path %v", got)
+	}
+}
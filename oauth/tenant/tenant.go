@@ -0,0 +1,234 @@
+// Package tenant provides a 2-legged authenticator for services acting for many
+// Forge apps, each with its own client id and secret.
+//
+// The tenant for a request is taken from the request context:
+//
+//	pool := tenant.NewPool()
+//	pool.Register("acme", oauth.AuthDataForClient(acmeID, acmeSecret))
+//	client := api.NewClient(pool)
+//	hubAPI := dm.HubAPI{Client: client}
+//	ctx := tenant.WithTenant(ctx, "acme")
+//	hub, err := hubAPI.GetHubDetails(hubKey, api.WithContext(ctx))
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/twolegged"
+)
+
+var (
+	// ErrNoTenant is returned when the request context does not carry a tenant
+	ErrNoTenant = errors.New("no tenant in context")
+	// ErrUnknownTenant is returned when the tenant is not registered and can not be looked up
+	ErrUnknownTenant = errors.New("unknown tenant")
+)
+
+type tenantKey struct{}
+
+// WithTenant returns a copy of ctx carrying the tenant key
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// From returns the tenant key carried by ctx
+func From(ctx context.Context) (tenant string, ok bool) {
+	tenant, ok = ctx.Value(tenantKey{}).(string)
+	return tenant, ok && tenant != ""
+}
+
+type entry struct {
+	auth     twolegged.Auth
+	lastUsed time.Time
+	// lookedUp is true when the credentials came from Pool.Lookup
+	lookedUp bool
+	// idle is true when the cached tokens have been dropped by Evict
+	idle bool
+}
+
+// Pool maps tenants to their credentials, keeping a separate token cache for
+// each tenant. It is a ForgeAuthenticator that picks the tenant from the request
+// context, see WithTenant.
+type Pool struct {
+	// Host used for the api paths of all tenants, if empty oauth.DefaultHost is used
+	Host string
+	// IdleTimeout is how long a tenant can go unused before being evicted by Evict.
+	// If 0, tenants are never evicted.
+	IdleTimeout time.Duration
+	// Lookup, if set, is used to load the credentials of tenants that are not registered
+	Lookup func(ctx context.Context, tenant string) (oauth.AuthData, error)
+	// Configure, if set, is called to configure the authenticator created for a tenant
+	Configure func(tenant string, auth *twolegged.Auth)
+
+	mutex   sync.Mutex
+	tenants map[string]*entry
+}
+
+// NewPool returns an empty pool
+func NewPool() *Pool {
+	return &Pool{tenants: make(map[string]*entry)}
+}
+
+func (p *Pool) newEntry(tenant string, data oauth.AuthData, lookedUp bool) *entry {
+	auth := twolegged.NewAuth(data.ClientID, data.ClientSecret.Reveal())
+	auth.Host = data.Host
+	auth.AuthenticationPath = data.AuthenticationPath
+	if p.Configure != nil {
+		p.Configure(tenant, &auth)
+	}
+	return &entry{auth: auth, lastUsed: time.Now(), lookedUp: lookedUp}
+}
+
+// Register adds the credentials for the tenant, replacing any existing ones along with
+// their cached tokens. Requests already in flight finish with the previous credentials,
+// so Register can be used to rotate credentials at runtime.
+func (p *Pool) Register(tenant string, data oauth.AuthData) {
+	e := p.newEntry(tenant, data, false)
+	p.mutex.Lock()
+	if p.tenants == nil {
+		p.tenants = make(map[string]*entry)
+	}
+	p.tenants[tenant] = e
+	p.mutex.Unlock()
+}
+
+// Rotate replaces the credentials of a registered tenant, see Register
+func (p *Pool) Rotate(tenant string, data oauth.AuthData) error {
+	e := p.newEntry(tenant, data, false)
+	// the lock is held across the check and the replacement, so a concurrent
+	// Remove is not undone
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, ok := p.tenants[tenant]; !ok {
+		return fmt.Errorf("%w %q", ErrUnknownTenant, tenant)
+	}
+	p.tenants[tenant] = e
+	return nil
+}
+
+// Remove drops the tenant and its cached tokens
+func (p *Pool) Remove(tenant string) {
+	p.mutex.Lock()
+	delete(p.tenants, tenant)
+	p.mutex.Unlock()
+}
+
+// Tenants returns the keys of the tenants currently in the pool
+func (p *Pool) Tenants() []string {
+	p.mutex.Lock()
+	keys := make([]string, 0, len(p.tenants))
+	for key := range p.tenants {
+		keys = append(keys, key)
+	}
+	p.mutex.Unlock()
+	sort.Strings(keys)
+	return keys
+}
+
+// Auth returns the authenticator for the tenant carried by ctx
+func (p *Pool) Auth(ctx context.Context) (twolegged.Auth, error) {
+	tenant, ok := From(ctx)
+	if !ok {
+		return twolegged.Auth{}, ErrNoTenant
+	}
+	now := time.Now()
+	p.mutex.Lock()
+	e, ok := p.tenants[tenant]
+	if ok {
+		e.lastUsed, e.idle = now, false
+		auth := e.auth
+		p.mutex.Unlock()
+		return auth, nil
+	}
+	p.mutex.Unlock()
+
+	if p.Lookup == nil {
+		return twolegged.Auth{}, fmt.Errorf("%w %q", ErrUnknownTenant, tenant)
+	}
+	data, err := p.Lookup(ctx, tenant)
+	if err != nil {
+		return twolegged.Auth{}, fmt.Errorf("tenant %q lookup: %w", tenant, err)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if e, ok = p.tenants[tenant]; !ok {
+		// another request may have loaded the tenant while we where looking it up
+		e = p.newEntry(tenant, data, true)
+		if p.tenants == nil {
+			p.tenants = make(map[string]*entry)
+		}
+		p.tenants[tenant] = e
+	}
+	e.lastUsed = now
+	return e.auth, nil
+}
+
+// Evict drops the tenants that have not been used for IdleTimeout, returning the
+// number of tenants evicted. Tenants loaded by Lookup are removed from the pool;
+// registered tenants keep their credentials but drop their cached tokens.
+func (p *Pool) Evict(now time.Time) (evicted int) {
+	if p.IdleTimeout <= 0 {
+		return 0
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for tenant, e := range p.tenants {
+		if e.idle || now.Sub(e.lastUsed) < p.IdleTimeout {
+			continue
+		}
+		if e.lookedUp {
+			delete(p.tenants, tenant)
+		} else if e.auth.Cache != nil {
+			e.auth.Cache.Clear()
+			e.idle = true
+		}
+		evicted++
+	}
+	return evicted
+}
+
+// Run evicts idle tenants periodically, until ctx is done
+func (p *Pool) Run(ctx context.Context) {
+	if p.IdleTimeout <= 0 {
+		return
+	}
+	ticker := time.NewTicker(p.IdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.Evict(now)
+		}
+	}
+}
+
+// Path returns the full url for the api paths on the pool's Host
+func (p *Pool) Path(paths ...string) string {
+	return oauth.AuthData{Host: p.Host}.Path(paths...)
+}
+
+// SetAuthHeader always fails, as there is no context to get the tenant from;
+// api.Client uses SetAuthHeaderContext.
+func (p *Pool) SetAuthHeader(scope scopes.Scope, header http.Header) error {
+	return ErrNoTenant
+}
+
+// SetAuthHeaderContext sets the auth headers using the credentials of the tenant carried by ctx
+func (p *Pool) SetAuthHeaderContext(ctx context.Context, scope scopes.Scope, header http.Header) error {
+	auth, err := p.Auth(ctx)
+	if err != nil {
+		return err
+	}
+	return auth.SetAuthHeaderContext(ctx, scope, header)
+}
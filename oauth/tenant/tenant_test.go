@@ -0,0 +1,167 @@
+package tenant_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/dm"
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/tenant"
+)
+
+// forgeServer hands out a token named after the client id, and records the
+// tokens used for api requests
+type forgeServer struct {
+	*httptest.Server
+
+	mutex           sync.Mutex
+	authentications map[string]int
+	used            []string
+}
+
+func newForgeServer(t *testing.T) *forgeServer {
+	t.Helper()
+	fs := &forgeServer{authentications: make(map[string]int)}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fs.mutex.Lock()
+		defer fs.mutex.Unlock()
+		if r.URL.Path == "/authentication/v1/authenticate" {
+			r.ParseForm()
+			id := r.PostForm.Get("client_id")
+			fs.authentications[id]++
+			w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"token-` + id + `"}`))
+			return
+		}
+		fs.used = append(fs.used, r.Header.Get(oauth.HeaderAuthorization))
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+func (fs *forgeServer) lastUsed() string {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	if len(fs.used) == 0 {
+		return ""
+	}
+	return fs.used[len(fs.used)-1]
+}
+
+func credentialsFor(server *forgeServer, id string) oauth.AuthData {
+	data := oauth.AuthDataForClient(id, "secret")
+	data.Host = server.URL
+	return data
+}
+
+func TestPool(t *testing.T) {
+	server := newForgeServer(t)
+	pool := tenant.NewPool()
+	pool.Host = server.URL
+	pool.Register("acme", credentialsFor(server, "acme-app"))
+	pool.Register("globex", credentialsFor(server, "globex-app"))
+
+	client := api.NewClient(pool)
+	get := func(ctx context.Context) error {
+		return client.Get(ctx, scopes.DataRead, []string{"project", "v1", "hubs"}, nil)
+	}
+	ctx := context.Background()
+
+	t.Run("Per tenant tokens", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			for _, name := range []string{"acme", "globex"} {
+				if err := get(tenant.WithTenant(ctx, name)); err != nil {
+					t.Fatalf("Unexpected error: %s\n", err.Error())
+				}
+				if used := server.lastUsed(); used != "Bearer token-"+name+"-app" {
+					t.Errorf("Expected %v token to be used, got %q", name, used)
+				}
+			}
+		}
+		if server.authentications["acme-app"] != 1 || server.authentications["globex-app"] != 1 {
+			t.Errorf("Expected one authentication per tenant, got %v", server.authentications)
+		}
+	})
+
+	t.Run("No tenant", func(t *testing.T) {
+		if err := get(ctx); !errors.Is(err, tenant.ErrNoTenant) {
+			t.Errorf("Expected ErrNoTenant, got %v", err)
+		}
+	})
+
+	t.Run("Unknown tenant", func(t *testing.T) {
+		if err := get(tenant.WithTenant(ctx, "initech")); !errors.Is(err, tenant.ErrUnknownTenant) {
+			t.Errorf("Expected ErrUnknownTenant, got %v", err)
+		}
+	})
+
+	t.Run("Through the dm API", func(t *testing.T) {
+		hubAPI := dm.HubAPI{Client: client}
+		if _, err := hubAPI.GetHubDetails("hub", api.WithContext(tenant.WithTenant(ctx, "globex"))); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if used := server.lastUsed(); used != "Bearer token-globex-app" {
+			t.Errorf("Expected the globex token to be used, got %q", used)
+		}
+		if _, err := hubAPI.GetHubDetails("hub"); !errors.Is(err, tenant.ErrNoTenant) {
+			t.Errorf("Expected ErrNoTenant without a context, got %v", err)
+		}
+	})
+
+	t.Run("Rotate credentials", func(t *testing.T) {
+		if err := pool.Rotate("acme", credentialsFor(server, "acme-app-v2")); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if err := get(tenant.WithTenant(ctx, "acme")); err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if used := server.lastUsed(); used != "Bearer token-acme-app-v2" {
+			t.Errorf("Expected the rotated token to be used, got %q", used)
+		}
+		if err := pool.Rotate("initech", credentialsFor(server, "initech-app")); !errors.Is(err, tenant.ErrUnknownTenant) {
+			t.Errorf("Expected ErrUnknownTenant, got %v", err)
+		}
+	})
+}
+
+func TestPool_LookupAndEvict(t *testing.T) {
+	server := newForgeServer(t)
+	var lookups int
+	pool := tenant.NewPool()
+	pool.Host = server.URL
+	pool.IdleTimeout = time.Minute
+	pool.Lookup = func(ctx context.Context, name string) (oauth.AuthData, error) {
+		lookups++
+		return credentialsFor(server, name+"-app"), nil
+	}
+	pool.Register("acme", credentialsFor(server, "acme-app"))
+
+	client := api.NewClient(pool)
+	ctx := tenant.WithTenant(context.Background(), "globex")
+	if err := client.Get(ctx, scopes.DataRead, []string{"project", "v1", "hubs"}, nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if used := server.lastUsed(); used != "Bearer token-globex-app" {
+		t.Errorf("Expected the looked up token to be used, got %q", used)
+	}
+	if lookups != 1 {
+		t.Errorf("Expected one lookup, got %d", lookups)
+	}
+
+	if evicted := pool.Evict(time.Now()); evicted != 0 {
+		t.Errorf("Expected no tenant to be evicted, got %d", evicted)
+	}
+	if evicted := pool.Evict(time.Now().Add(2 * time.Minute)); evicted != 2 {
+		t.Errorf("Expected two tenants to be evicted, got %d", evicted)
+	}
+	if tenants := pool.Tenants(); len(tenants) != 1 || tenants[0] != "acme" {
+		t.Errorf("Expected only the registered tenant to remain, got %v", tenants)
+	}
+}
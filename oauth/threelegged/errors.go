@@ -0,0 +1,53 @@
+package threelegged
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+var (
+	// ErrInvalidState is returned when a state value was not created by the StateManager
+	// or has been tampered with
+	ErrInvalidState = errors.New("invalid oauth state")
+	// ErrExpiredState is returned when a state value is older than the StateManager's TTL
+	ErrExpiredState = errors.New("expired oauth state")
+	// ErrStateMismatch is returned when the state value does not belong to the browser
+	// session that started the login
+	ErrStateMismatch = errors.New("oauth state does not match session")
+	// ErrMissingCode is returned when the callback did not receive an authorization code
+	ErrMissingCode = errors.New("missing authorization code")
+)
+
+// ErrAuthorization reflects an OAuth error response sent to the redirect uri
+// ref: https://tools.ietf.org/html/rfc6749#section-4.1.2.1
+type ErrAuthorization struct {
+	Code        string // error
+	Description string // error_description
+	URI         string // error_uri
+	State       string // state
+}
+
+// errAuthorizationFrom returns an ErrAuthorization if the values contain an error response
+func errAuthorizationFrom(values url.Values) error {
+	code := values.Get("error")
+	if code == "" {
+		return nil
+	}
+	return ErrAuthorization{
+		Code:        code,
+		Description: values.Get("error_description"),
+		URI:         values.Get("error_uri"),
+		State:       values.Get("state"),
+	}
+}
+
+func (err ErrAuthorization) Error() string {
+	if err.Description == "" {
+		return fmt.Sprintf("authorization failed: %v", err.Code)
+	}
+	return fmt.Sprintf("authorization failed: %v: %v", err.Code, err.Description)
+}
+
+// IsAccessDenied returns true if the end user declined to give consent
+func (err ErrAuthorization) IsAccessDenied() bool { return err.Code == "access_denied" }
@@ -0,0 +1,129 @@
+package threelegged
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultReturnParam is the query parameter the LoginHandler reads the return url from
+const DefaultReturnParam = "return"
+
+// LoginHandler starts the 3-legged flow; it binds a new state to the browser with
+// a cookie and redirects the user to the Forge authorization page.
+type LoginHandler struct {
+	Auth   Auth
+	States *StateManager
+	// ReturnParam is the query parameter holding the (relative) url to send the
+	// user to once logged in. If empty DefaultReturnParam is used.
+	ReturnParam string
+}
+
+func (h LoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	param := h.ReturnParam
+	if param == "" {
+		param = DefaultReturnParam
+	}
+	value, state, err := h.States.New(safeReturnURL(r.URL.Query().Get(param)))
+	if err != nil {
+		http.Error(w, "unable to start login", http.StatusInternalServerError)
+		return
+	}
+	location, err := h.Auth.Authorize(value)
+	if err != nil {
+		http.Error(w, "unable to start login", http.StatusInternalServerError)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.States.cookieName(),
+		Value:    state.Nonce,
+		Path:     "/",
+		Expires:  state.Expires,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, location, http.StatusFound)
+}
+
+// safeReturnURL only allows local paths, to prevent the login from being used as an open redirect
+func safeReturnURL(str string) string {
+	if !strings.HasPrefix(str, "/") || strings.HasPrefix(str, "//") || strings.HasPrefix(str, "/\\") {
+		return ""
+	}
+	u, err := url.Parse(str)
+	if err != nil || u.IsAbs() || u.Host != "" {
+		return ""
+	}
+	return str
+}
+
+// CallbackHandler handles the redirect back from the Forge authorization page; it checks
+// the state, exchanges the code for a token, and calls OnToken with the result.
+type CallbackHandler struct {
+	Auth   Auth
+	States *StateManager
+	// OnToken is called with the token and the return url carried by the state
+	OnToken func(w http.ResponseWriter, r *http.Request, token AuthToken, returnURL string)
+	// OnError is called when the authorization failed. Errors will be of type ErrAuthorization
+	// when the authorization server reported an error. If nil, a plain http error is written.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+func (h CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	var nonce string
+	if cookie, err := r.Cookie(h.States.cookieName()); err == nil {
+		nonce = cookie.Value
+	}
+	// the state is single use; clear it whatever the outcome
+	http.SetCookie(w, &http.Cookie{
+		Name:     h.States.cookieName(),
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	state, err := h.States.VerifyNonce(query.Get("state"), nonce)
+	if err != nil {
+		h.error(w, r, err)
+		return
+	}
+	if err = errAuthorizationFrom(query); err != nil {
+		h.error(w, r, err)
+		return
+	}
+	code := query.Get("code")
+	if code == "" {
+		h.error(w, r, ErrMissingCode)
+		return
+	}
+	token, err := h.Auth.AuthToken(code)
+	if err != nil {
+		h.error(w, r, err)
+		return
+	}
+	h.OnToken(w, r, token, state.ReturnURL)
+}
+
+func (h CallbackHandler) error(w http.ResponseWriter, r *http.Request, err error) {
+	if h.OnError != nil {
+		h.OnError(w, r, err)
+		return
+	}
+	var errAuth ErrAuthorization
+	switch {
+	case errors.As(err, &errAuth) && errAuth.IsAccessDenied():
+		http.Error(w, "access denied", http.StatusForbidden)
+	case errors.As(err, &errAuth),
+		errors.Is(err, ErrInvalidState),
+		errors.Is(err, ErrExpiredState),
+		errors.Is(err, ErrStateMismatch),
+		errors.Is(err, ErrMissingCode):
+		http.Error(w, "invalid authorization response", http.StatusBadRequest)
+	default:
+		http.Error(w, "unable to complete login", http.StatusBadGateway)
+	}
+}
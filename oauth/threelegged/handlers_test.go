@@ -0,0 +1,186 @@
+package threelegged_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/threelegged"
+)
+
+func newTokenServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/authentication/v1/gettoken" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "good-code" {
+			http.Error(w, `{"developerMessage":"bad code"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"access","refresh_token":"refresh"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestStateManager(t *testing.T) {
+	states := threelegged.NewStateManager([]byte("0123456789abcdef0123456789abcdef"))
+
+	value, state, err := states.New("/projects")
+	if err != nil {
+		t.Fatalf("Failed to create state: %s\n", err.Error())
+	}
+
+	t.Run("Verify valid state", func(t *testing.T) {
+		got, err := states.VerifyNonce(value, state.Nonce)
+		if err != nil {
+			t.Fatalf("Failed to verify state: %s\n", err.Error())
+		}
+		if got.ReturnURL != "/projects" {
+			t.Errorf("Expected return url /projects, got %q", got.ReturnURL)
+		}
+	})
+
+	t.Run("Tampered state", func(t *testing.T) {
+		_, err := states.Verify(value[:len(value)-2] + "xx")
+		if !errors.Is(err, threelegged.ErrInvalidState) {
+			t.Errorf("Expected ErrInvalidState, got %v", err)
+		}
+	})
+
+	t.Run("State signed with another key", func(t *testing.T) {
+		other := threelegged.NewStateManager([]byte("another key that is long enough!"))
+		_, err := other.Verify(value)
+		if !errors.Is(err, threelegged.ErrInvalidState) {
+			t.Errorf("Expected ErrInvalidState, got %v", err)
+		}
+	})
+
+	t.Run("Wrong session", func(t *testing.T) {
+		_, err := states.VerifyNonce(value, "some other nonce")
+		if !errors.Is(err, threelegged.ErrStateMismatch) {
+			t.Errorf("Expected ErrStateMismatch, got %v", err)
+		}
+	})
+
+	t.Run("Expired state", func(t *testing.T) {
+		shortLived := &threelegged.StateManager{Key: states.Key, TTL: -time.Second}
+		expired, _, err := shortLived.New("")
+		if err != nil {
+			t.Fatalf("Failed to create state: %s\n", err.Error())
+		}
+		if _, err = shortLived.Verify(expired); !errors.Is(err, threelegged.ErrExpiredState) {
+			t.Errorf("Expected ErrExpiredState, got %v", err)
+		}
+	})
+}
+
+func TestLoginAndCallbackHandler(t *testing.T) {
+	server := newTokenServer(t)
+
+	auth := threelegged.NewAuth("id", "secret", "http://localhost:3009/callback", scopes.DataRead)
+	auth.Host = server.URL
+	states := threelegged.NewStateManager([]byte("0123456789abcdef0123456789abcdef"))
+
+	login := threelegged.LoginHandler{Auth: auth, States: states}
+
+	var (
+		gotToken  threelegged.AuthToken
+		gotReturn string
+		gotErr    error
+	)
+	callback := threelegged.CallbackHandler{
+		Auth:   auth,
+		States: states,
+		OnToken: func(w http.ResponseWriter, r *http.Request, token threelegged.AuthToken, returnURL string) {
+			gotToken, gotReturn = token, returnURL
+		},
+		OnError: func(w http.ResponseWriter, r *http.Request, err error) {
+			gotErr = err
+		},
+	}
+
+	startLogin := func(t *testing.T, returnURL string) (state string, cookie *http.Cookie) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		login.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/login?return="+url.QueryEscape(returnURL), nil))
+		if rec.Code != http.StatusFound {
+			t.Fatalf("Expected redirect, got %d", rec.Code)
+		}
+		location, err := url.Parse(rec.Header().Get("Location"))
+		if err != nil {
+			t.Fatalf("Bad redirect location: %s\n", err.Error())
+		}
+		cookies := rec.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("Expected one cookie, got %d", len(cookies))
+		}
+		return location.Query().Get("state"), cookies[0]
+	}
+
+	callBack := func(query url.Values, cookie *http.Cookie) {
+		gotToken, gotReturn, gotErr = threelegged.AuthToken{}, "", nil
+		req := httptest.NewRequest(http.MethodGet, "/callback?"+query.Encode(), nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		callback.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("Successful login", func(t *testing.T) {
+		state, cookie := startLogin(t, "/projects?id=1")
+		callBack(url.Values{"state": {state}, "code": {"good-code"}}, cookie)
+		if gotErr != nil {
+			t.Fatalf("Unexpected error: %s\n", gotErr.Error())
+		}
+		if gotToken.Token == nil || gotToken.Token.Bearer().AccessToken != "access" {
+			t.Errorf("Expected to receive the access token")
+		}
+		if gotReturn != "/projects?id=1" {
+			t.Errorf("Expected return url /projects?id=1, got %q", gotReturn)
+		}
+	})
+
+	t.Run("Open redirect is dropped", func(t *testing.T) {
+		state, cookie := startLogin(t, "https://evil.example.com/")
+		callBack(url.Values{"state": {state}, "code": {"good-code"}}, cookie)
+		if gotErr != nil {
+			t.Fatalf("Unexpected error: %s\n", gotErr.Error())
+		}
+		if gotReturn != "" {
+			t.Errorf("Expected empty return url, got %q", gotReturn)
+		}
+	})
+
+	t.Run("Missing session cookie", func(t *testing.T) {
+		state, _ := startLogin(t, "")
+		callBack(url.Values{"state": {state}, "code": {"good-code"}}, nil)
+		if !errors.Is(gotErr, threelegged.ErrStateMismatch) {
+			t.Errorf("Expected ErrStateMismatch, got %v", gotErr)
+		}
+	})
+
+	t.Run("Access denied", func(t *testing.T) {
+		state, cookie := startLogin(t, "")
+		callBack(url.Values{"state": {state}, "error": {"access_denied"}, "error_description": {"user declined"}}, cookie)
+		var errAuth threelegged.ErrAuthorization
+		if !errors.As(gotErr, &errAuth) || !errAuth.IsAccessDenied() {
+			t.Errorf("Expected access denied ErrAuthorization, got %v", gotErr)
+		}
+	})
+
+	t.Run("Bad code", func(t *testing.T) {
+		state, cookie := startLogin(t, "")
+		callBack(url.Values{"state": {state}, "code": {"bad-code"}}, cookie)
+		if gotErr == nil {
+			t.Errorf("Expected the code exchange to fail")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package threelegged
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+)
+
+var (
+	// ErrRedirectMismatch is returned when the redirect url does not match the Auth's RedirectURI
+	ErrRedirectMismatch = errors.New("redirect does not match the redirect uri")
+	// ErrMissingToken is returned when the redirect carries neither an access token nor a code
+	ErrMissingToken = errors.New("missing access token")
+	// ErrNoRefreshToken is returned when refreshing a token that came without a
+	// refresh token, as the ones from the implicit grant. A new token has to be
+	// requested through Authorize instead.
+	ErrNoRefreshToken = errors.New("token can not be refreshed: no refresh token")
+)
+
+// ImplicitResponse reflects the fragment of the redirect of the implicit grant
+// (Auth.Implicate), g.e. #access_token=...&token_type=Bearer&expires_in=3599&state=...
+type ImplicitResponse struct {
+	Bearer oauth.Bearer
+	State  string
+	// Code is set by hybrid responses, it can be exchanged for a refreshable token with Auth.AuthToken
+	Code string
+}
+
+// ParseImplicitRedirect parses the url the end user was redirected to after an implicit or
+// hybrid authorization. The url must match the RedirectURI, minus its query and fragment.
+// OAuth error responses are returned as ErrAuthorization.
+//
+//	Note: the fragment is never sent to the server; it has to be forwarded by the
+//	browser, g.e. by a script posting window.location.href.
+func (a Auth) ParseImplicitRedirect(redirect string) (response ImplicitResponse, err error) {
+	got, err := url.Parse(redirect)
+	if err != nil {
+		return response, fmt.Errorf("parsing redirect: %w", err)
+	}
+	want, err := url.Parse(a.RedirectURI)
+	if err != nil {
+		return response, fmt.Errorf("parsing redirect uri: %w", err)
+	}
+	if !strings.EqualFold(got.Scheme, want.Scheme) || !strings.EqualFold(got.Host, want.Host) ||
+		strings.TrimSuffix(got.Path, "/") != strings.TrimSuffix(want.Path, "/") {
+		return response, fmt.Errorf("%w: got %v://%v%v", ErrRedirectMismatch, got.Scheme, got.Host, got.Path)
+	}
+
+	values, err := url.ParseQuery(got.EscapedFragment())
+	if err != nil {
+		return response, fmt.Errorf("parsing redirect fragment: %w", err)
+	}
+	// errors may be sent in the query, g.e. when the request itself is invalid
+	if err = errAuthorizationFrom(values); err != nil {
+		return response, err
+	}
+	if err = errAuthorizationFrom(got.Query()); err != nil {
+		return response, err
+	}
+
+	response.State = values.Get("state")
+	response.Code = values.Get("code")
+	response.Bearer = oauth.Bearer{
+		TokenType:   values.Get("token_type"),
+		AccessToken: values.Get("access_token"),
+	}
+	if response.Bearer.AccessToken == "" && response.Code == "" {
+		return response, ErrMissingToken
+	}
+	if response.Bearer.TokenType == "" {
+		response.Bearer.TokenType = "Bearer"
+	}
+	if expiresIn := values.Get("expires_in"); expiresIn != "" {
+		seconds, err := strconv.ParseInt(expiresIn, 10, 32)
+		if err != nil {
+			return response, fmt.Errorf("invalid expires_in %q: %w", expiresIn, err)
+		}
+		response.Bearer.ExpiresIn = int32(seconds)
+	}
+	return response, nil
+}
+
+// ImplicitAuthToken returns a ForgeAuthenticator for the access token of an implicit
+// response. As there is no refresh token, it fails with ErrNoRefreshToken once the
+// token expires.
+func (a Auth) ImplicitAuthToken(response ImplicitResponse) (AuthToken, error) {
+	authTkn := AuthToken{Auth: a}
+	if response.Bearer.AccessToken == "" {
+		return authTkn, ErrMissingToken
+	}
+	bearer := response.Bearer
+	bearer.RefreshToken = ""
+	authTkn.Token = NewRefreshableToken(&bearer)
+	authTkn.Token.Observer = a.observer()
+	authTkn.Token.Observer.Emit(oauth.TokenEvent{Kind: oauth.TokenAcquired, ExpiresAt: authTkn.Token.ExpiresAt()})
+	return authTkn, nil
+}
@@ -0,0 +1,92 @@
+package threelegged_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/threelegged"
+)
+
+func TestAuth_ParseImplicitRedirect(t *testing.T) {
+	auth := threelegged.NewAuth("client", "secret", "https://example.com/callback", scopes.DataRead)
+	auth.Implicate = true
+
+	tests := []struct {
+		name     string
+		redirect string
+		token    string
+		code     string
+		state    string
+		expires  int32
+		err      error
+	}{
+		{
+			name:     "implicit",
+			redirect: "https://example.com/callback#access_token=abc&token_type=Bearer&expires_in=3599&state=xyz",
+			token:    "abc", state: "xyz", expires: 3599,
+		},
+		{
+			name:     "hybrid",
+			redirect: "https://example.com/callback/#code=the-code&access_token=abc&expires_in=60",
+			token:    "abc", code: "the-code", expires: 60,
+		},
+		{
+			name:     "other host",
+			redirect: "https://evil.example.com/callback#access_token=abc",
+			err:      threelegged.ErrRedirectMismatch,
+		},
+		{
+			name:     "other path",
+			redirect: "https://example.com/other#access_token=abc",
+			err:      threelegged.ErrRedirectMismatch,
+		},
+		{
+			name:     "missing token",
+			redirect: "https://example.com/callback#state=xyz",
+			err:      threelegged.ErrMissingToken,
+		},
+		{
+			name:     "access denied",
+			redirect: "https://example.com/callback#error=access_denied&state=xyz",
+			err:      threelegged.ErrAuthorization{Code: "access_denied", State: "xyz"},
+		},
+		{
+			name:     "error in query",
+			redirect: "https://example.com/callback?error=invalid_scope&error_description=bad+scope",
+			err:      threelegged.ErrAuthorization{Code: "invalid_scope", Description: "bad scope"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			response, err := auth.ParseImplicitRedirect(tc.redirect)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("Expected error %v, got %v", tc.err, err)
+			}
+			if err != nil {
+				return
+			}
+			if response.Bearer.AccessToken != tc.token || response.Code != tc.code ||
+				response.State != tc.state || response.Bearer.ExpiresIn != tc.expires {
+				t.Errorf("Unexpected response: %+v", response)
+			}
+		})
+	}
+}
+
+func TestAuth_ImplicitAuthToken(t *testing.T) {
+	auth := threelegged.NewAuth("client", "secret", "https://example.com/callback", scopes.DataRead)
+	response, err := auth.ParseImplicitRedirect("https://example.com/callback#access_token=abc&expires_in=0")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	token, err := auth.ImplicitAuthToken(response)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	// the token expired straight away, and can not be refreshed
+	if err := token.SetAuthHeader(scopes.DataRead, make(http.Header)); !errors.Is(err, threelegged.ErrNoRefreshToken) {
+		t.Errorf("Expected ErrNoRefreshToken, got %v", err)
+	}
+}
@@ -2,15 +2,119 @@ package threelegged
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth"
 	"github.com/gdey/forge-api-go-client/oauth/scopes"
 )
 
 const (
 	DefaultInformationalAPIPath = "userprofile/v1"
+	// DefaultUserInfoURL is the OpenID Connect userinfo endpoint
+	DefaultUserInfoURL = "https://api.userprofile.autodesk.com/userinfo"
+	// MaxProfileImageSize is the largest profile image, in bytes, ProfileImage will download
+	MaxProfileImageSize = 1 << 20
 )
 
+var (
+	// OpAboutMe is the operation for retrieving the profile of the authorizing end user
+	OpAboutMe = api.RegisterOperation(api.Operation{
+		Name: "userprofile.me.get", Method: http.MethodGet, Scope: scopes.UserProfileRead,
+	})
+	// OpUserInfo is the operation for retrieving the OpenID Connect claims of the authorizing end user
+	OpUserInfo = api.RegisterOperation(api.Operation{
+		Name: "userprofile.userinfo.get", Method: http.MethodGet, Scope: scopes.OpenID,
+	})
+)
+
+// ImageSize is the height and width, in pixels, of a square profile image
+type ImageSize int
+
+// The profile image sizes provided by Forge
+const (
+	ImageSize20  ImageSize = 20
+	ImageSize40  ImageSize = 40
+	ImageSize50  ImageSize = 50
+	ImageSize58  ImageSize = 58
+	ImageSize80  ImageSize = 80
+	ImageSize120 ImageSize = 120
+	ImageSize160 ImageSize = 160
+	ImageSize176 ImageSize = 176
+	ImageSize240 ImageSize = 240
+	ImageSize360 ImageSize = 360
+)
+
+const imageSizePrefix = "sizeX"
+
+func (size ImageSize) String() string { return imageSizePrefix + strconv.Itoa(int(size)) }
+
+// ProfileImages maps the available image sizes to the URLs for downloading them
+type ProfileImages map[ImageSize]string
+
+// MarshalJSON encodes the images in the Forge format, g.e. {"sizeX20": "https://..."}
+func (images ProfileImages) MarshalJSON() ([]byte, error) {
+	values := make(map[string]string, len(images))
+	for size, url := range images {
+		values[size.String()] = url
+	}
+	return json.Marshal(values)
+}
+
+// UnmarshalJSON decodes the images from the Forge format, ignoring unknown attributes
+func (images *ProfileImages) UnmarshalJSON(data []byte) error {
+	var values map[string]string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*images = make(ProfileImages, len(values))
+	for key, url := range values {
+		if !strings.HasPrefix(key, imageSizePrefix) {
+			continue
+		}
+		size, err := strconv.Atoi(strings.TrimPrefix(key, imageSizePrefix))
+		if err != nil {
+			continue
+		}
+		(*images)[ImageSize(size)] = url
+	}
+	return nil
+}
+
+// Sizes returns the available sizes, smallest first
+func (images ProfileImages) Sizes() []ImageSize {
+	sizes := make([]ImageSize, 0, len(images))
+	for size := range images {
+		sizes = append(sizes, size)
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i] < sizes[j] })
+	return sizes
+}
+
+// Closest returns the smallest available image at least as large as size, or the
+// largest one if there is none. ok is false if there are no images.
+func (images ProfileImages) Closest(size ImageSize) (closest ImageSize, url string, ok bool) {
+	sizes := images.Sizes()
+	if len(sizes) == 0 {
+		return 0, "", false
+	}
+	closest = sizes[len(sizes)-1]
+	for _, s := range sizes {
+		if s >= size {
+			closest = s
+			break
+		}
+	}
+	return closest, images[closest], true
+}
+
 // UserProfile reflects the response received when query the profile of an authorizing end user in a 3-legged context
 type UserProfile struct {
 	UserID    string `json:"userId"`    // The backend user ID of the profile
@@ -25,14 +129,74 @@ type UserProfile struct {
 	// A flat JSON object of attribute-value pairs in which the attributes specify available profile image sizes in the
 	// format sizeX<pixels> (where <pixels> is an integer that represents both height and width in pixels of square
 	// profile images) and the values are URLs for downloading the images via HTTP
-	ProfileImages interface{} `json:"profileImages"`
+	ProfileImages ProfileImages `json:"profileImages"`
+}
+
+// UserInfo reflects the OpenID Connect claims of an authorizing end user
+// ref: https://openid.net/specs/openid-connect-core-1_0.html#StandardClaims
+type UserInfo struct {
+	Subject           string `json:"sub"`                // The backend user ID of the profile
+	Name              string `json:"name"`               // The user's full name
+	GivenName         string `json:"given_name"`         // The user's first name
+	FamilyName        string `json:"family_name"`        // The user's last name
+	PreferredUsername string `json:"preferred_username"` // The username chosen by the user
+	Email             string `json:"email"`              // The user's email address
+	EmailVerified     bool   `json:"email_verified"`     // true if the user's email address has been verified
+	Profile           string `json:"profile"`            // The URL of the user's profile page
+	Picture           string `json:"picture"`            // The URL of the user's profile image
+	Locale            string `json:"locale"`             // The user's locale, g.e. en-US
+	UpdatedAt         int64  `json:"updated_at"`         // When the profile was last updated, in seconds since the epoch
 }
 
 // Information struct is holding the host and path used when making queries
-// for profile of an authorizing end user in a 3-legged context
+// for profile of an authorizing end user in a 3-legged context.
+// Use NewInformation to share one api client across the calls.
 type Information struct {
 	APIPath string
+	// UserInfoURL is the userinfo endpoint, if empty DefaultUserInfoURL is used
+	UserInfoURL string
+	// HTTPClient, if set, is used for all requests; so connections can be reused across calls.
+	// If nil http.DefaultClient is used.
+	HTTPClient *http.Client
 	AuthToken
+
+	apiClient *api.Client
+}
+
+// NewInformation returns an Information for token whose requests all go through the same
+// api client. httpClient may be nil to use http.DefaultClient.
+func NewInformation(token AuthToken, httpClient *http.Client) Information {
+	info := Information{HTTPClient: httpClient, AuthToken: token}
+	info.apiClient = info.newClient()
+	return info
+}
+
+// client returns the api client built by NewInformation, or a new one if there is none
+func (info Information) client() *api.Client {
+	if info.apiClient != nil {
+		return info.apiClient
+	}
+	return info.newClient()
+}
+
+func (info Information) newClient() *api.Client {
+	client := api.NewClient(absoluteAuth{info.AuthToken})
+	if info.HTTPClient != nil {
+		client.Client = *info.HTTPClient
+	}
+	return client
+}
+
+// absoluteAuth passes absolute urls, g.e. the userinfo endpoint, through as they are
+type absoluteAuth struct {
+	oauth.ForgeAuthenticator
+}
+
+func (auth absoluteAuth) Path(paths ...string) string {
+	if len(paths) == 1 && (strings.HasPrefix(paths[0], "https://") || strings.HasPrefix(paths[0], "http://")) {
+		return paths[0]
+	}
+	return auth.ForgeAuthenticator.Path(paths...)
 }
 
 func (info Information) Path(paths ...string) []string {
@@ -44,14 +208,65 @@ func (info Information) Path(paths ...string) []string {
 
 //AboutMe is used to get the profile of an authorizing end user, given the token obtained via 3-legged OAuth flow
 func (info Information) AboutMe() (profile UserProfile, err error) {
+	return info.AboutMeContext(context.Background())
+}
 
-	client := api.NewClient(info.AuthToken)
-	err = client.Get(
-		context.Background(),
-		scopes.UserProfileRead,
+// AboutMeContext is like AboutMe, with a context for the request
+func (info Information) AboutMeContext(ctx context.Context) (profile UserProfile, err error) {
+	err = info.client().Get(
+		OpAboutMe.Context(ctx),
+		OpAboutMe.Scope,
 		info.Path("users/@me"),
 		&profile,
-		nil,
 	)
 	return profile, err
 }
+
+// UserInfo is used to get the OpenID Connect claims of an authorizing end user
+func (info Information) UserInfo(ctx context.Context) (userInfo UserInfo, err error) {
+	url := info.UserInfoURL
+	if url == "" {
+		url = DefaultUserInfoURL
+	}
+	err = info.client().Get(
+		OpUserInfo.Context(ctx),
+		OpUserInfo.Scope,
+		[]string{url},
+		&userInfo,
+	)
+	return userInfo, err
+}
+
+// ProfileImage downloads the profile image of the user closest to the given size, see
+// ProfileImages.Closest. It returns the image along with its content type.
+// Images larger than MaxProfileImageSize are rejected.
+func (info Information) ProfileImage(ctx context.Context, profile UserProfile, size ImageSize) (image []byte, contentType string, err error) {
+	_, url, ok := profile.ProfileImages.Closest(size)
+	if !ok {
+		return nil, "", fmt.Errorf("user %v has no profile image", profile.UserID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	client := http.DefaultClient
+	if info.HTTPClient != nil {
+		client = info.HTTPClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer res.Body.Close()
+	content, err := ioutil.ReadAll(io.LimitReader(res.Body, MaxProfileImageSize+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, "", api.ErrResult{StatusCode: res.StatusCode, Reason: string(content)}
+	}
+	if len(content) > MaxProfileImageSize {
+		return nil, "", fmt.Errorf("profile image of user %v is larger than %d bytes", profile.UserID, MaxProfileImageSize)
+	}
+	return content, res.Header.Get("Content-Type"), nil
+}
@@ -0,0 +1,84 @@
+package threelegged
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultProfileTTL is how long a profile is kept by a ProfileCache
+const DefaultProfileTTL = 5 * time.Minute
+
+type cachedProfile struct {
+	profile UserProfile
+	expires time.Time
+}
+
+// ProfileCache keeps the profiles of the end users per access token, so the profile
+// can be looked up on every request without calling Forge every time. Only a hash
+// of the access tokens is kept.
+type ProfileCache struct {
+	// TTL is how long a profile is kept, if 0 DefaultProfileTTL is used
+	TTL time.Duration
+
+	mutex    sync.Mutex
+	profiles map[[sha256.Size]byte]cachedProfile
+}
+
+// NewProfileCache returns an empty cache keeping profiles for ttl
+func NewProfileCache(ttl time.Duration) *ProfileCache {
+	return &ProfileCache{TTL: ttl}
+}
+
+func (c *ProfileCache) ttl() time.Duration {
+	if c.TTL == 0 {
+		return DefaultProfileTTL
+	}
+	return c.TTL
+}
+
+// AboutMe returns the cached profile for the access token of info, calling
+// info.AboutMeContext if there is none or it has expired.
+func (c *ProfileCache) AboutMe(ctx context.Context, info Information) (UserProfile, error) {
+	if info.Token == nil {
+		return UserProfile{}, errors.New("Invalid Token")
+	}
+	key := sha256.Sum256([]byte(info.Token.Bearer().AccessToken))
+	now := time.Now()
+
+	c.mutex.Lock()
+	cached, ok := c.profiles[key]
+	c.mutex.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.profile, nil
+	}
+
+	profile, err := info.AboutMeContext(ctx)
+	if err != nil {
+		return profile, err
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if c.profiles == nil {
+		c.profiles = make(map[[sha256.Size]byte]cachedProfile)
+	}
+	// drop the expired profiles, so tokens that are no longer used do not pile up
+	for k, p := range c.profiles {
+		if !now.Before(p.expires) {
+			delete(c.profiles, k)
+		}
+	}
+	c.profiles[key] = cachedProfile{profile: profile, expires: now.Add(c.ttl())}
+	return profile, nil
+}
+
+// Forget drops the cached profile for the access token, g.e. on logout
+func (c *ProfileCache) Forget(accessToken string) {
+	key := sha256.Sum256([]byte(accessToken))
+	c.mutex.Lock()
+	delete(c.profiles, key)
+	c.mutex.Unlock()
+}
@@ -0,0 +1,164 @@
+package threelegged_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+	"github.com/gdey/forge-api-go-client/oauth/threelegged"
+)
+
+// newProfileServer serves the profile, userinfo and a profile image, counting the profile requests
+func newProfileServer(t *testing.T, requests *int) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(oauth.HeaderAuthorization) != "Bearer user-token" && !strings.HasPrefix(r.URL.Path, "/images/") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/userprofile/v1/users/@me":
+			*requests++
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"userId": "the-user",
+				"profileImages": map[string]string{
+					"sizeX20":  server.URL + "/images/20.png",
+					"sizeX40":  server.URL + "/images/40.png",
+					"sizeX360": server.URL + "/images/360.png",
+				},
+			})
+		case "/userinfo":
+			w.Write([]byte(`{"sub":"the-user","email":"user@example.com","email_verified":true}`))
+		case "/images/40.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("png"))
+		case "/images/360.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(make([]byte, threelegged.MaxProfileImageSize+1))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newInformation(server *httptest.Server) threelegged.Information {
+	auth := threelegged.NewAuth("client", "secret", "https://example.com/callback", scopes.UserProfileRead|scopes.OpenID)
+	auth.Host = server.URL
+	info := threelegged.NewInformation(threelegged.AuthToken{
+		Auth: auth,
+		Token: threelegged.NewRefreshableToken(&oauth.Bearer{
+			TokenType: "Bearer", ExpiresIn: 3599, AccessToken: "user-token",
+		}),
+	}, server.Client())
+	info.UserInfoURL = server.URL + "/userinfo"
+	return info
+}
+
+func TestInformation(t *testing.T) {
+	var requests int
+	server := newProfileServer(t, &requests)
+	info := newInformation(server)
+	ctx := context.Background()
+
+	profile, err := info.AboutMeContext(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if sizes := profile.ProfileImages.Sizes(); len(sizes) != 3 || sizes[0] != threelegged.ImageSize20 {
+		t.Errorf("Unexpected profile images: %v", profile.ProfileImages)
+	}
+
+	t.Run("Closest image", func(t *testing.T) {
+		tests := []struct {
+			size, closest threelegged.ImageSize
+		}{
+			{size: threelegged.ImageSize20, closest: threelegged.ImageSize20},
+			{size: 30, closest: threelegged.ImageSize40},
+			{size: 1000, closest: threelegged.ImageSize360},
+		}
+		for _, tc := range tests {
+			if closest, _, _ := profile.ProfileImages.Closest(tc.size); closest != tc.closest {
+				t.Errorf("Expected size %v for %v, got %v", tc.closest, tc.size, closest)
+			}
+		}
+	})
+
+	t.Run("Download image", func(t *testing.T) {
+		image, contentType, err := info.ProfileImage(ctx, profile, 32)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if string(image) != "png" || contentType != "image/png" {
+			t.Errorf("Unexpected image %q of type %q", image, contentType)
+		}
+	})
+
+	t.Run("Image too large", func(t *testing.T) {
+		if _, _, err := info.ProfileImage(ctx, profile, threelegged.ImageSize360); err == nil {
+			t.Errorf("Expected an error for an image over %d bytes", threelegged.MaxProfileImageSize)
+		}
+	})
+
+	t.Run("Information literal", func(t *testing.T) {
+		literal := threelegged.Information{
+			UserInfoURL: info.UserInfoURL,
+			HTTPClient:  info.HTTPClient,
+			AuthToken:   info.AuthToken,
+		}
+		userInfo, err := literal.UserInfo(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if userInfo.Subject != "the-user" {
+			t.Errorf("Unexpected userinfo: %+v", userInfo)
+		}
+	})
+
+	t.Run("UserInfo", func(t *testing.T) {
+		userInfo, err := info.UserInfo(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if userInfo.Subject != "the-user" || userInfo.Email != "user@example.com" || !userInfo.EmailVerified {
+			t.Errorf("Unexpected userinfo: %+v", userInfo)
+		}
+	})
+}
+
+func TestProfileCache(t *testing.T) {
+	var requests int
+	server := newProfileServer(t, &requests)
+	info := newInformation(server)
+	cache := threelegged.NewProfileCache(time.Minute)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		profile, err := cache.AboutMe(ctx, info)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if profile.UserID != "the-user" {
+			t.Errorf("Unexpected profile: %+v", profile)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the profile to be requested once, got %d", requests)
+	}
+
+	cache.Forget("user-token")
+	if _, err := cache.AboutMe(ctx, info); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if requests != 2 {
+		t.Errorf("Expected the profile to be requested again, got %d", requests)
+	}
+}
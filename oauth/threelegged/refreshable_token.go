@@ -8,19 +8,33 @@ import (
 	"github.com/gdey/forge-api-go-client/oauth"
 )
 
+// RefreshableToken is a 3-legged token that can be refreshed; it is safe for concurrent use
 type RefreshableToken struct {
-	bearer          *oauth.Bearer
+	// bearer is replaced, never modified, when the token is refreshed
+	bearer *oauth.Bearer
+	// TokenExpireTime is the expiry of the access token, read it with ExpiresAt
 	TokenExpireTime time.Time
-	readMutex       sync.Mutex
-	writeMutex      sync.Mutex
+	// Observer, if set, is called with the lifecycle events of the token
+	Observer oauth.TokenObserver
+
+	mutex sync.RWMutex
+	// refreshing is the refresh in flight, shared by the concurrent refreshes
+	refreshing *refreshCall
+}
+
+// refreshCall is a refresh of the token, waited on by all the callers that asked for it
+type refreshCall struct {
+	done chan struct{}
+	err  error
 }
 
 func NewRefreshableToken(bearer *oauth.Bearer) *RefreshableToken {
 
 	now := time.Now()
 	expiryTime := now.Add(time.Second * time.Duration(bearer.ExpiresIn))
+	copied := *bearer
 	return &RefreshableToken{
-		bearer:          bearer,
+		bearer:          &copied,
 		TokenExpireTime: expiryTime,
 	}
 }
@@ -31,35 +45,97 @@ func (t *RefreshableToken) RefreshTokenIfRequired(auth AuthRefresher) error {
 	}
 
 	// Check if token has expired
-	now := time.Now()
-	expiryTime := t.TokenExpireTime
-	if now.Before(expiryTime) {
+	expiryTime := t.ExpiresAt()
+	if time.Now().Before(expiryTime) {
 		return nil
 	}
+	t.Observer.Emit(oauth.TokenEvent{Kind: oauth.TokenExpired, ExpiresAt: expiryTime})
+	return t.refresh(auth)
+}
+
+// Refresh gets a new access token, even if the current one has not expired yet
+func (t *RefreshableToken) Refresh(auth AuthRefresher) error {
+	if t == nil {
+		return errors.New("Invalid Token")
+	}
+	return t.refresh(auth)
+}
+
+// refresh refreshes the token; concurrent calls share the same refresh, so the
+// single-use refresh token is only spent once
+func (t *RefreshableToken) refresh(auth AuthRefresher) error {
+	t.mutex.Lock()
+	if call := t.refreshing; call != nil {
+		t.mutex.Unlock()
+		<-call.done
+		return call.err
+	}
+	call := &refreshCall{done: make(chan struct{})}
+	t.refreshing = call
+	refreshToken := t.bearer.RefreshToken
+	t.mutex.Unlock()
+
+	call.err = t.exchange(auth, refreshToken)
+
+	t.mutex.Lock()
+	t.refreshing = nil
+	t.mutex.Unlock()
+	close(call.done)
+	return call.err
+}
 
-	refreshedBearer, err := auth.RefreshToken(t.bearer.RefreshToken)
+// exchange exchanges the refresh token for a new bearer
+func (t *RefreshableToken) exchange(auth AuthRefresher, refreshToken string) error {
+	if refreshToken == "" {
+		t.Observer.Emit(oauth.TokenEvent{Kind: oauth.TokenRefreshFailed, Err: ErrNoRefreshToken})
+		return ErrNoRefreshToken
+	}
+	refreshedBearer, err := auth.RefreshToken(refreshToken)
 	if err != nil {
+		t.Observer.Emit(oauth.TokenEvent{Kind: oauth.TokenRefreshFailed, Err: err})
 		return err
 	}
 
 	// Refresh "now" and add new token expiration time to API struct along with new credentials
-	now = time.Now()
+	now := time.Now()
 	newExpiryTime := now.Add(time.Second * time.Duration(refreshedBearer.ExpiresIn))
 
-	t.writeMutex.Lock()
+	bearer := *refreshedBearer
+	t.mutex.Lock()
 	t.TokenExpireTime = newExpiryTime
+	t.bearer = &bearer
+	t.mutex.Unlock()
 
-	t.bearer.AccessToken = refreshedBearer.AccessToken
-	t.bearer.ExpiresIn = refreshedBearer.ExpiresIn
-	t.bearer.RefreshToken = refreshedBearer.RefreshToken
-	t.bearer.TokenType = refreshedBearer.TokenType
-	t.writeMutex.Unlock()
-
+	t.Observer.Emit(oauth.TokenEvent{Kind: oauth.TokenRefreshed, ExpiresAt: newExpiryTime})
 	return nil
 }
 
+// ExpiresAt returns the expiry time of the current access token
+func (t *RefreshableToken) ExpiresAt() time.Time {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	return t.TokenExpireTime
+}
+
+// Bearer returns the current bearer; it is not modified by later refreshes
 func (t *RefreshableToken) Bearer() *oauth.Bearer {
-	t.readMutex.Lock()
-	defer t.readMutex.Unlock()
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
 	return t.bearer
 }
+
+// Claims decodes the claims of the current access token without verifying its signature
+func (t *RefreshableToken) Claims() (*oauth.Claims, error) {
+	if t == nil {
+		return nil, errors.New("Invalid Token")
+	}
+	return t.Bearer().Claims()
+}
+
+// VerifiedClaims decodes the claims of the current access token after checking its signature
+func (t *RefreshableToken) VerifiedClaims(source oauth.KeySource) (*oauth.Claims, error) {
+	if t == nil {
+		return nil, errors.New("Invalid Token")
+	}
+	return t.Bearer().VerifiedClaims(source)
+}
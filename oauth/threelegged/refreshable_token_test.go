@@ -0,0 +1,115 @@
+package threelegged_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/threelegged"
+)
+
+type refresher struct {
+	err error
+}
+
+func (r refresher) RefreshToken(refreshToken string) (*oauth.Bearer, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &oauth.Bearer{TokenType: "Bearer", ExpiresIn: 3599, AccessToken: "refreshed", RefreshToken: refreshToken}, nil
+}
+
+func TestRefreshableToken_Observer(t *testing.T) {
+	var events []oauth.TokenEventKind
+	token := threelegged.NewRefreshableToken(&oauth.Bearer{AccessToken: "expired", RefreshToken: "refresh"})
+	token.Observer = func(event oauth.TokenEvent) { events = append(events, event.Kind) }
+
+	failure := errors.New("refresh failed")
+	if err := token.RefreshTokenIfRequired(refresher{err: failure}); err != failure {
+		t.Fatalf("Expected the refresh error, got %v", err)
+	}
+	if err := token.RefreshTokenIfRequired(refresher{}); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if token.Bearer().AccessToken != "refreshed" || !token.ExpiresAt().After(time.Now()) {
+		t.Errorf("Expected the token to be refreshed, got %+v", token.Bearer())
+	}
+	// the token is still valid, so nothing happens
+	if err := token.RefreshTokenIfRequired(refresher{}); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+
+	expected := []oauth.TokenEventKind{oauth.TokenExpired, oauth.TokenRefreshFailed, oauth.TokenExpired, oauth.TokenRefreshed}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, events)
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("Expected events %v, got %v", expected, events)
+			break
+		}
+	}
+}
+
+// rotatingRefresher hands out single-use refresh tokens, failing if one is spent twice
+type rotatingRefresher struct {
+	calls int32
+	mutex sync.Mutex
+	spent map[string]bool
+}
+
+func (r *rotatingRefresher) RefreshToken(refreshToken string) (*oauth.Bearer, error) {
+	n := atomic.AddInt32(&r.calls, 1)
+	time.Sleep(10 * time.Millisecond)
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.spent[refreshToken] {
+		return nil, fmt.Errorf("refresh token %v already used", refreshToken)
+	}
+	r.spent[refreshToken] = true
+	return &oauth.Bearer{
+		TokenType:    "Bearer",
+		ExpiresIn:    3599,
+		AccessToken:  fmt.Sprintf("access-%d", n),
+		RefreshToken: fmt.Sprintf("refresh-%d", n),
+	}, nil
+}
+
+func TestRefreshableToken_Concurrent(t *testing.T) {
+	token := threelegged.NewRefreshableToken(&oauth.Bearer{AccessToken: "expired", RefreshToken: "refresh-0"})
+	auth := &rotatingRefresher{spent: make(map[string]bool)}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		// the background refresher and the requests refreshing an expired token
+		go func() {
+			defer wg.Done()
+			errs <- token.Refresh(auth)
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- token.RefreshTokenIfRequired(auth)
+			_ = token.Bearer().AccessToken
+			_ = token.ExpiresAt()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Unexpected error: %s\n", err.Error())
+		}
+	}
+	if calls := atomic.LoadInt32(&auth.calls); calls >= 20 {
+		t.Errorf("Expected the concurrent refreshes to be shared, got %d calls", calls)
+	}
+	if bearer := token.Bearer(); bearer.AccessToken == "expired" {
+		t.Errorf("Expected the token to be refreshed, got %+v", bearer)
+	}
+}
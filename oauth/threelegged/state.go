@@ -0,0 +1,143 @@
+package threelegged
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultStateTTL is the time a login has to complete before the state expires
+	DefaultStateTTL = 10 * time.Minute
+	// DefaultStateCookieName is the cookie used to bind a state to a browser session
+	DefaultStateCookieName = "forge_oauth_state"
+)
+
+// State is the information carried by a signed state value
+type State struct {
+	Nonce     string    // Nonce is the value bound to the browser session
+	ReturnURL string    // ReturnURL is where the user should be sent after login
+	Expires   time.Time // Expires is when the state is no longer accepted
+}
+
+type statePayload struct {
+	Nonce     string `json:"n"`
+	Expires   int64  `json:"e"`
+	ReturnURL string `json:"r,omitempty"`
+}
+
+// StateManager creates and verifies signed, expiring state values for the
+// 3-legged authorization flow.
+type StateManager struct {
+	// Key is used to sign the state values, it should be at least 32 random bytes
+	Key []byte
+	// TTL is how long a state value is valid, if 0 DefaultStateTTL is used
+	TTL time.Duration
+	// CookieName is the name of the cookie used to bind the state to the browser,
+	// if empty DefaultStateCookieName is used
+	CookieName string
+}
+
+// NewStateManager returns a StateManager that signs state values with the given key
+func NewStateManager(key []byte) *StateManager {
+	return &StateManager{Key: key}
+}
+
+func (m *StateManager) ttl() time.Duration {
+	if m.TTL == 0 {
+		return DefaultStateTTL
+	}
+	return m.TTL
+}
+
+func (m *StateManager) cookieName() string {
+	if m.CookieName == "" {
+		return DefaultStateCookieName
+	}
+	return m.CookieName
+}
+
+func (m *StateManager) sign(payload string) []byte {
+	mac := hmac.New(sha256.New, m.Key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// New returns a new signed state value carrying the returnURL along with the decoded State
+func (m *StateManager) New(returnURL string) (value string, state State, err error) {
+	if len(m.Key) == 0 {
+		return "", state, errors.New("state manager key not set")
+	}
+	nonce := make([]byte, 16)
+	if _, err = rand.Read(nonce); err != nil {
+		return "", state, err
+	}
+	state = State{
+		Nonce:     base64.RawURLEncoding.EncodeToString(nonce),
+		ReturnURL: returnURL,
+		Expires:   time.Now().Add(m.ttl()).Truncate(time.Second),
+	}
+	content, err := json.Marshal(statePayload{
+		Nonce:     state.Nonce,
+		Expires:   state.Expires.Unix(),
+		ReturnURL: state.ReturnURL,
+	})
+	if err != nil {
+		return "", state, err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(content)
+	value = payload + "." + base64.RawURLEncoding.EncodeToString(m.sign(payload))
+	return value, state, nil
+}
+
+// Verify checks the signature and expiry of the state value and returns the decoded State
+func (m *StateManager) Verify(value string) (state State, err error) {
+	if len(m.Key) == 0 {
+		return state, errors.New("state manager key not set")
+	}
+	idx := strings.IndexByte(value, '.')
+	if idx == -1 {
+		return state, ErrInvalidState
+	}
+	payload := value[:idx]
+	signature, err := base64.RawURLEncoding.DecodeString(value[idx+1:])
+	if err != nil || !hmac.Equal(signature, m.sign(payload)) {
+		return state, ErrInvalidState
+	}
+	content, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return state, ErrInvalidState
+	}
+	var decoded statePayload
+	if err = json.Unmarshal(content, &decoded); err != nil {
+		return state, ErrInvalidState
+	}
+	state = State{
+		Nonce:     decoded.Nonce,
+		ReturnURL: decoded.ReturnURL,
+		Expires:   time.Unix(decoded.Expires, 0),
+	}
+	if !time.Now().Before(state.Expires) {
+		return state, ErrExpiredState
+	}
+	return state, nil
+}
+
+// VerifyNonce checks the state value like Verify, and additional checks that it was issued
+// for the session holding nonce
+func (m *StateManager) VerifyNonce(value, nonce string) (state State, err error) {
+	state, err = m.Verify(value)
+	if err != nil {
+		return state, err
+	}
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(nonce), []byte(state.Nonce)) != 1 {
+		return state, ErrStateMismatch
+	}
+	return state, nil
+}
@@ -4,11 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/gdey/forge-api-go-client/api"
 	"github.com/gdey/forge-api-go-client/oauth"
@@ -31,6 +33,20 @@ func (a AuthToken) GetTokenWithScope(scope scopes.Scope) (*oauth.Bearer, error)
 	return a.Token.Bearer(), nil
 }
 
+// KeepFresh refreshes the token ahead of its expiry, until ctx is done, so requests
+// do not wait on the refresh. It blocks, g.e.
+//
+//	go token.KeepFresh(ctx, oauth.Refresher{Jitter: time.Minute})
+func (a AuthToken) KeepFresh(ctx context.Context, refresher oauth.Refresher) error {
+	if a.Token == nil {
+		return errors.New("Invalid Token")
+	}
+	return refresher.Run(ctx, a.Token.ExpiresAt(), func() (time.Time, error) {
+		err := a.Token.Refresh(a.Auth)
+		return a.Token.ExpiresAt(), err
+	})
+}
+
 func (a AuthToken) SetAuthHeader(scope scopes.Scope, header http.Header) error {
 
 	bearer, err := a.GetTokenWithScope(scope)
@@ -51,7 +67,13 @@ type Auth struct {
 	// Do not use this unless you know what you are doing.
 	Implicate bool
 
-	client api.Client
+	// Observer, if set, is called with the lifecycle events of the tokens
+	Observer oauth.TokenObserver
+	// HTTPClient, if set, is used for the authentication requests; g.e. to set a timeout.
+	// If nil a zero http.Client is used.
+	HTTPClient *http.Client
+	// Log, if set, logs the authentication requests with their secrets redacted
+	Log *api.Logging
 }
 
 // Authenticator interface defines the method necessary to qualify as 3-legged authenticator
@@ -114,12 +136,12 @@ func (a Auth) GetToken(code string) (bearer oauth.Bearer, err error) {
 
 	body := url.Values{
 		"client_id":     []string{a.ClientID},
-		"client_secret": []string{a.ClientSecret},
+		"client_secret": []string{a.ClientSecret.Reveal()},
 		"grant_type":    []string{"authorization_code"},
 		"code":          []string{code},
 		"redirect_uri":  []string{a.RedirectURI},
 	}
-	res, err := a.client.DoRawRequest(context.Background(), http.MethodPost, 0,
+	res, err := a.client().DoRawRequest(context.Background(), http.MethodPost, 0,
 		a.AuthPath("gettoken"),
 		nil, nil,
 		api.ContentTypeFormEncoded,
@@ -154,22 +176,35 @@ func (a Auth) AuthToken(code string) (AuthToken, error) {
 	}
 
 	authTkn.Token = NewRefreshableToken(&bearer)
+	authTkn.Token.Observer = a.observer()
+	authTkn.Token.Observer.Emit(oauth.TokenEvent{Kind: oauth.TokenAcquired, ExpiresAt: authTkn.Token.ExpiresAt()})
 	return authTkn, nil
 }
 
+// observer returns the Observer adding the client id and scope to the events
+func (a Auth) observer() oauth.TokenObserver {
+	if a.Observer == nil {
+		return nil
+	}
+	return func(event oauth.TokenEvent) {
+		event.ClientID, event.Scope = a.ClientID, a.Scope
+		a.Observer(event)
+	}
+}
+
 // RefreshToken is used to get a new access token by using the refresh token provided by GetToken
 func (a Auth) RefreshToken(refreshToken string) (bearer *oauth.Bearer, err error) {
 	bearer = new(oauth.Bearer)
 
 	body := url.Values{
 		"client_id":     []string{a.ClientID},
-		"client_secret": []string{a.ClientSecret},
+		"client_secret": []string{a.ClientSecret.Reveal()},
 		"grant_type":    []string{"refresh_token"},
 		"refresh_token": []string{refreshToken},
 		"scope":         []string{a.Scope.String()},
 	}
 
-	res, err := a.client.DoRawRequest(context.Background(), http.MethodPost, 0,
+	res, err := a.client().DoRawRequest(context.Background(), http.MethodPost, 0,
 		a.AuthPath("refreshtoken"),
 		nil, nil,
 		api.ContentTypeFormEncoded,
@@ -193,3 +228,12 @@ func (a Auth) RefreshToken(refreshToken string) (bearer *oauth.Bearer, err error
 
 	return bearer, nil
 }
+
+// client returns the api client used for the authentication requests
+func (a Auth) client() *api.Client {
+	client := &api.Client{Log: a.Log}
+	if a.HTTPClient != nil {
+		client.Client = *a.HTTPClient
+	}
+	return client
+}
@@ -0,0 +1,121 @@
+package twolegged
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// DefaultExpiryLeeway is how long before its expiry a cached token is replaced
+const DefaultExpiryLeeway = 30 * time.Second
+
+type cachedToken struct {
+	bearer  *oauth.Bearer
+	expires time.Time
+}
+
+type tokenCall struct {
+	done   chan struct{}
+	bearer *oauth.Bearer
+	err    error
+}
+
+// TokenCache keeps the tokens, per scope, so they can be reused until they expire.
+// Concurrent requests for the same scope share a single authentication call.
+type TokenCache struct {
+	// Leeway is how long before expiry a token is replaced, if 0 DefaultExpiryLeeway is used
+	Leeway time.Duration
+
+	mutex    sync.Mutex
+	tokens   map[scopes.Scope]cachedToken
+	inflight map[scopes.Scope]*tokenCall
+}
+
+// NewTokenCache returns an empty token cache
+func NewTokenCache() *TokenCache {
+	return &TokenCache{}
+}
+
+func (c *TokenCache) leeway() time.Duration {
+	if c.Leeway == 0 {
+		return DefaultExpiryLeeway
+	}
+	return c.Leeway
+}
+
+// Token returns the cached token for the scope, calling fetch to get a new one
+// if there is no token or it is about to expire.
+func (c *TokenCache) Token(scope scopes.Scope, fetch func() (*oauth.Bearer, error)) (*oauth.Bearer, error) {
+	return c.get(scope, false, fetch, nil)
+}
+
+// Refresh calls fetch to replace the cached token for the scope, even if it has not expired
+func (c *TokenCache) Refresh(scope scopes.Scope, fetch func() (*oauth.Bearer, error)) (*oauth.Bearer, error) {
+	return c.get(scope, true, fetch, nil)
+}
+
+// get returns the cached token, fetching a new one when needed or forced. The
+// lifecycle events of the token are emitted to emit, if not nil.
+func (c *TokenCache) get(scope scopes.Scope, force bool, fetch func() (*oauth.Bearer, error), emit oauth.TokenObserver) (*oauth.Bearer, error) {
+	now := time.Now()
+	c.mutex.Lock()
+	token, cached := c.tokens[scope]
+	if cached && !force && now.Add(c.leeway()).Before(token.expires) {
+		c.mutex.Unlock()
+		return token.bearer, nil
+	}
+	if call, ok := c.inflight[scope]; ok {
+		c.mutex.Unlock()
+		<-call.done
+		return call.bearer, call.err
+	}
+	call := &tokenCall{done: make(chan struct{})}
+	if c.inflight == nil {
+		c.inflight = make(map[scopes.Scope]*tokenCall)
+	}
+	c.inflight[scope] = call
+	c.mutex.Unlock()
+
+	if cached && !now.Before(token.expires) {
+		emit.Emit(oauth.TokenEvent{Kind: oauth.TokenExpired, Scope: scope, ExpiresAt: token.expires})
+	}
+	call.bearer, call.err = fetch()
+
+	c.mutex.Lock()
+	delete(c.inflight, scope)
+	var expires time.Time
+	if call.err == nil {
+		expires = c.put(scope, call.bearer)
+	}
+	c.mutex.Unlock()
+	close(call.done)
+
+	switch {
+	case call.err != nil:
+		emit.Emit(oauth.TokenEvent{Kind: oauth.TokenRefreshFailed, Scope: scope, Err: call.err})
+	case cached:
+		emit.Emit(oauth.TokenEvent{Kind: oauth.TokenRefreshed, Scope: scope, ExpiresAt: expires})
+	default:
+		emit.Emit(oauth.TokenEvent{Kind: oauth.TokenAcquired, Scope: scope, ExpiresAt: expires})
+	}
+	return call.bearer, call.err
+}
+
+// put stores the token, returning its expiry time; the mutex must be held
+func (c *TokenCache) put(scope scopes.Scope, bearer *oauth.Bearer) time.Time {
+	if c.tokens == nil {
+		c.tokens = make(map[scopes.Scope]cachedToken)
+	}
+	expires := time.Now().Add(time.Duration(bearer.ExpiresIn) * time.Second)
+	c.tokens[scope] = cachedToken{bearer: bearer, expires: expires}
+	return expires
+}
+
+// Clear drops all the cached tokens
+func (c *TokenCache) Clear() {
+	c.mutex.Lock()
+	c.tokens = nil
+	c.mutex.Unlock()
+}
@@ -8,19 +8,39 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gdey/forge-api-go-client/api"
 	"github.com/gdey/forge-api-go-client/oauth"
 	"github.com/gdey/forge-api-go-client/oauth/scopes"
 )
 
+// AuditRecord describes the app acting on the behalf of a user
+type AuditRecord struct {
+	ClientID string       // ClientID is the app identity
+	UserID   string       // UserID is the impersonated user
+	Scope    scopes.Scope // Scope is the scope of the token used
+	Time     time.Time
+}
+
 // Auth struct holds data necessary for making requests in 2-legged context
 type Auth struct {
 	oauth.AuthData
-	// UserID is the user to act on the behalf of.
+	// UserID is the user to act on the behalf of. It is overridden by a user id
+	// set on the request context with oauth.WithUserID.
 	UserID string
-
-	client api.Client
+	// Audit, if set, is called every time a request is made on the behalf of a user
+	Audit func(ctx context.Context, record AuditRecord)
+	// Cache, if set, is used to reuse tokens until they expire. Copies of
+	// the Auth share the same cache.
+	Cache *TokenCache
+	// Observer, if set, is called with the lifecycle events of the tokens
+	Observer oauth.TokenObserver
+	// HTTPClient, if set, is used for the authentication requests; g.e. to set a timeout.
+	// If nil a zero http.Client is used.
+	HTTPClient *http.Client
+	// Log, if set, logs the authentication requests with their secrets redacted
+	Log *api.Logging
 }
 
 // Authenticator interface defines the method necessary to qualify as 2-legged authenticator
@@ -28,16 +48,69 @@ type Authenticator interface {
 	Authenticate(scope scopes.Scope) (*oauth.Bearer, error)
 }
 
-// NewClient returns a 2-legged authenticator with default host and authPath
+// NewAuth returns a 2-legged authenticator with default host and authPath, that caches its tokens
 func NewAuth(clientID, clientSecret string) Auth {
 	return Auth{
 		AuthData: oauth.AuthDataForClient(clientID, clientSecret),
+		Cache:    NewTokenCache(),
 	}
 }
 
-// GetTokenWithScope will get the a token for the given scope
+// GetTokenWithScope will get the a token for the given scope, reusing a cached token if possible
 func (a Auth) GetTokenWithScope(scope scopes.Scope) (*oauth.Bearer, error) {
-	return a.Authenticate(scope)
+	if a.Cache == nil {
+		bearer, err := a.Authenticate(scope)
+		if err != nil {
+			a.emit(oauth.TokenEvent{Kind: oauth.TokenRefreshFailed, Scope: scope, Err: err})
+			return nil, err
+		}
+		a.emit(oauth.TokenEvent{
+			Kind:      oauth.TokenAcquired,
+			Scope:     scope,
+			ExpiresAt: time.Now().Add(time.Duration(bearer.ExpiresIn) * time.Second),
+		})
+		return bearer, nil
+	}
+	return a.Cache.get(scope, false, a.fetch(scope), a.emit)
+}
+
+// RefreshToken replaces the cached token for the scope, even if it has not expired yet.
+// Without a Cache, it just gets a new token.
+func (a Auth) RefreshToken(scope scopes.Scope) (*oauth.Bearer, error) {
+	if a.Cache == nil {
+		return a.GetTokenWithScope(scope)
+	}
+	return a.Cache.get(scope, true, a.fetch(scope), a.emit)
+}
+
+// KeepFresh refreshes the cached token for the scope ahead of its expiry, until ctx
+// is done, so requests do not wait on authentication. It blocks, g.e.
+//
+//	go auth.KeepFresh(ctx, scopes.DataRead, oauth.Refresher{Jitter: time.Minute})
+func (a Auth) KeepFresh(ctx context.Context, scope scopes.Scope, refresher oauth.Refresher) error {
+	if a.Cache == nil {
+		return errors.New("KeepFresh requires a token cache")
+	}
+	return refresher.Run(ctx, time.Time{}, func() (time.Time, error) {
+		bearer, err := a.RefreshToken(scope)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Now().Add(time.Duration(bearer.ExpiresIn) * time.Second), nil
+	})
+}
+
+func (a Auth) fetch(scope scopes.Scope) func() (*oauth.Bearer, error) {
+	return func() (*oauth.Bearer, error) { return a.Authenticate(scope) }
+}
+
+// emit sends the event to the Observer, adding the client id
+func (a Auth) emit(event oauth.TokenEvent) {
+	if a.Observer == nil {
+		return
+	}
+	event.ClientID = a.ClientID
+	a.Observer.Emit(event)
 }
 
 // Authenticate allows getting a token with a given scope
@@ -51,12 +124,12 @@ func (a Auth) Authenticate(scope scopes.Scope) (bearer *oauth.Bearer, err error)
 
 	body := url.Values{
 		"client_id":     []string{a.ClientID},
-		"client_secret": []string{a.ClientSecret},
+		"client_secret": []string{a.ClientSecret.Reveal()},
 		"grant_type":    []string{"client_credentials"},
 		"scope":         []string{scope.String()},
 	}
 
-	res, err := a.client.DoRawRequest(context.Background(), "POST", 0,
+	res, err := a.client().DoRawRequest(context.Background(), "POST", 0,
 		a.AuthPath("authenticate"),
 		nil, nil,
 		"application/x-www-form-urlencoded",
@@ -77,20 +150,49 @@ func (a Auth) Authenticate(scope scopes.Scope) (bearer *oauth.Bearer, err error)
 	}
 
 	decoder := json.NewDecoder(res.Body)
-	err = decoder.Decode(bearer)
-
+	if err = decoder.Decode(bearer); err != nil {
+		return nil, err
+	}
 	return bearer, nil
 }
 
 func (a Auth) SetAuthHeader(scope scopes.Scope, header http.Header) error {
+	return a.SetAuthHeaderContext(context.Background(), scope, header)
+}
+
+// SetAuthHeaderContext is like SetAuthHeader, but will act on the behalf of the
+// user set on the ctx with oauth.WithUserID, if any.
+func (a Auth) SetAuthHeaderContext(ctx context.Context, scope scopes.Scope, header http.Header) error {
 
 	bearer, err := a.GetTokenWithScope(scope)
 	if err != nil {
 		return err
 	}
 	header.Set(oauth.HeaderAuthorization, "Bearer "+bearer.AccessToken)
-	if a.UserID != "" {
-		header.Set(oauth.HeaderXUserID, a.UserID)
+	userID := a.UserID
+	if id, ok := oauth.UserIDFrom(ctx); ok {
+		userID = id
+	}
+	if userID == "" {
+		return nil
+	}
+	header.Set(oauth.HeaderXUserID, userID)
+	if a.Audit != nil {
+		a.Audit(ctx, AuditRecord{
+			ClientID: a.ClientID,
+			UserID:   userID,
+			Scope:    scope,
+			Time:     time.Now(),
+		})
 	}
 	return nil
 }
+
+// client returns the api client used for the authentication requests
+func (a Auth) client() *api.Client {
+	client := &api.Client{Log: a.Log}
+	if a.HTTPClient != nil {
+		client.Client = *a.HTTPClient
+	}
+	return client
+}
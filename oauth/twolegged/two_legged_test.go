@@ -1,16 +1,137 @@
 package twolegged_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/dm"
 	"github.com/gdey/forge-api-go-client/env"
+	"github.com/gdey/forge-api-go-client/oauth"
 	"github.com/gdey/forge-api-go-client/oauth/scopes"
 	"github.com/gdey/forge-api-go-client/oauth/twolegged"
 )
 
+// newForgeServer returns a server that hands out tokens and records the
+// x-user-id header of api requests
+func newForgeServer(t *testing.T, userIDs *[]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/authentication/v1/authenticate" {
+			w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"app-token"}`))
+			return
+		}
+		*userIDs = append(*userIDs, r.Header.Get(oauth.HeaderXUserID))
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAuth_Impersonation(t *testing.T) {
+	var userIDs []string
+	server := newForgeServer(t, &userIDs)
+
+	var records []twolegged.AuditRecord
+	auth := twolegged.NewAuth("the-client", "secret")
+	auth.Host = server.URL
+	auth.Audit = func(ctx context.Context, record twolegged.AuditRecord) {
+		records = append(records, record)
+	}
+	client := api.NewClient(auth)
+	paths := []string{"project", "v1", "hubs"}
+
+	ctx := context.Background()
+	if err := client.Get(oauth.WithUserID(ctx, "user-1"), scopes.DataRead, paths, nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if err := client.Get(oauth.WithUserID(ctx, "user-2"), scopes.DataRead, paths, nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if err := client.Get(ctx, scopes.DataRead, paths, nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+
+	if len(userIDs) != 3 || userIDs[0] != "user-1" || userIDs[1] != "user-2" || userIDs[2] != "" {
+		t.Errorf("Expected x-user-id headers [user-1 user-2 ''], got %q", userIDs)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected 2 audit records, got %d", len(records))
+	}
+	if records[0].ClientID != "the-client" || records[0].UserID != "user-1" || records[0].Scope != scopes.DataRead {
+		t.Errorf("Unexpected audit record: %+v", records[0])
+	}
+}
+
+func TestAuth_ImpersonationThroughFolderAPI(t *testing.T) {
+	var userIDs []string
+	server := newForgeServer(t, &userIDs)
+	auth := twolegged.NewAuth("the-client", "secret")
+	auth.Host = server.URL
+	folderAPI := dm.FolderAPI{Client: api.NewClient(auth)}
+
+	ctx := oauth.WithUserID(context.Background(), "user-1")
+	if _, err := folderAPI.GetFolderDetails("project", "folder", api.WithContext(ctx)); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if _, err := folderAPI.GetFolderDetails("project", "folder"); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if len(userIDs) != 2 || userIDs[0] != "user-1" || userIDs[1] != "" {
+		t.Errorf("Expected x-user-id headers [user-1 ''], got %q", userIDs)
+	}
+}
+
+func TestAuth_KeepFresh(t *testing.T) {
+	var (
+		mutex           sync.Mutex
+		authentications int
+		events          []oauth.TokenEventKind
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		authentications++
+		mutex.Unlock()
+		w.Write([]byte(`{"token_type":"Bearer","expires_in":1,"access_token":"app-token"}`))
+	}))
+	defer server.Close()
+
+	auth := twolegged.NewAuth("the-client", "secret")
+	auth.Host = server.URL
+	auth.Observer = func(event oauth.TokenEvent) {
+		if event.ClientID != "the-client" || event.Scope != scopes.DataRead {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+		mutex.Lock()
+		events = append(events, event.Kind)
+		mutex.Unlock()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1400*time.Millisecond)
+	defer cancel()
+	// tokens living 1 second are refreshed every half second
+	if err := auth.KeepFresh(ctx, scopes.DataRead, oauth.Refresher{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected KeepFresh to stop with the context, got %v", err)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	if authentications < 3 {
+		t.Errorf("Expected at least 3 authentications, got %d", authentications)
+	}
+	if len(events) != authentications || events[0] != oauth.TokenAcquired || events[1] != oauth.TokenRefreshed {
+		t.Errorf("Expected an acquired event followed by refreshed events, got %v", events)
+	}
+}
+
 func TestAuthenticate(t *testing.T) {
 
 	clientID, clientSecret := env.GetClientSecretTest(t)
@@ -75,7 +196,7 @@ func TestAuthenticate(t *testing.T) {
 	})
 }
 
-func ExampleTwoLeggedAuth_Authenticate() {
+func ExampleAuth_Authenticate() {
 
 	// acquire Forge secrets from environment
 	clientID, clientSecret := os.Getenv("FORGE_CLIENT_ID"), os.Getenv("FORGE_CLIENT_SECRET")
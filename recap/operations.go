@@ -0,0 +1,34 @@
+package recap
+
+import (
+	"net/http"
+
+	clientapi "github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/oauth/scopes"
+)
+
+// Operations for the Reality Capture API
+// ref: https://forge.autodesk.com/en/docs/reality-capture/v1/reference/http/
+var (
+	OpCreatePhotoScene = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "recap.photoscene.create", Method: http.MethodPost, Scope: scopes.DataWrite,
+	})
+	OpAddFiles = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "recap.files.upload", Method: http.MethodPost, Scope: scopes.DataWrite,
+	})
+	OpStartSceneProcessing = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "recap.photoscene.process", Method: http.MethodPost, Scope: scopes.DataWrite, Idempotent: true,
+	})
+	OpGetSceneProgress = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "recap.photoscene.progress", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpGetSceneResults = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "recap.photoscene.results", Method: http.MethodGet, Scope: scopes.DataRead,
+	})
+	OpCancelSceneProcessing = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "recap.photoscene.cancel", Method: http.MethodPost, Scope: scopes.DataWrite, Idempotent: true,
+	})
+	OpDeleteScene = clientapi.RegisterOperation(clientapi.Operation{
+		Name: "recap.photoscene.delete", Method: http.MethodDelete, Scope: scopes.DataWrite,
+	})
+)
@@ -0,0 +1,31 @@
+package recap_test
+
+import (
+	"testing"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/api/apitest"
+	"github.com/gdey/forge-api-go-client/recap"
+)
+
+func TestOperationScopes(t *testing.T) {
+	server := apitest.NewServer(t)
+	auth := server.Auth()
+	recapAPI := recap.API{Client: api.NewClient(auth), ForgeAuthenticator: auth}
+
+	apitest.TestOperationScopes(t, server, []apitest.OperationCase{
+		{Op: recap.OpCreatePhotoScene, Call: func() error {
+			_, err := recapAPI.CreatePhotoScene("scene", []string{"obj"}, "object")
+			return err
+		}},
+		{Op: recap.OpAddFiles, Call: func() error {
+			_, err := recapAPI.AddFileToSceneUsingLink("scene", "https://example.com/image.jpg")
+			return err
+		}},
+		{Op: recap.OpStartSceneProcessing, Call: func() error { _, err := recapAPI.StartSceneProcessing("scene"); return err }},
+		{Op: recap.OpGetSceneProgress, Call: func() error { _, err := recapAPI.GetSceneProgress("scene"); return err }},
+		{Op: recap.OpGetSceneResults, Call: func() error { _, err := recapAPI.GetSceneResults("scene", "obj"); return err }},
+		{Op: recap.OpCancelSceneProcessing, Call: func() error { _, err := recapAPI.CancelSceneProcessing("scene"); return err }},
+		{Op: recap.OpDeleteScene, Call: func() error { _, err := recapAPI.DeleteScene("scene"); return err }},
+	})
+}
@@ -15,15 +15,14 @@ import (
 	"fmt"
 	"math/rand"
 	"mime/multipart"
-	"net/http"
 	"net/url"
 	"strings"
 
 	"github.com/gdey/forge-api-go-client/api/filters"
 
 	clientapi "github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/config"
 	"github.com/gdey/forge-api-go-client/oauth"
-	"github.com/gdey/forge-api-go-client/oauth/scopes"
 	"github.com/gdey/forge-api-go-client/oauth/twolegged"
 )
 
@@ -48,6 +47,19 @@ func NewAPIWithCredentials(ClientID string, ClientSecret string) API {
 	}
 }
 
+// NewAPIFromConfig returns a ReCap API client for the host, paths and credentials of cfg
+func NewAPIFromConfig(cfg config.Config) (API, error) {
+	client, err := cfg.Client(context.Background())
+	if err != nil {
+		return API{}, err
+	}
+	return API{
+		ForgeAuthenticator: client.ForgeAuthenticator,
+		Client:             client,
+		APIPath:            cfg.Paths.Recap,
+	}, nil
+}
+
 func (api API) Path(paths ...string) []string {
 	if api.APIPath == "" {
 		return append([]string{DefaultRecapAPIPath}, paths...)
@@ -59,7 +71,7 @@ func (api API) Path(paths ...string) []string {
 // 	name - should not be empty
 // 	formats - should be of type rcm, rcs, obj, ortho or report
 // 	sceneType - should be either "aerial" or "object"
-func (api API) CreatePhotoScene(name string, formats []string, sceneType string) (scene PhotoScene, err error) {
+func (api API) CreatePhotoScene(name string, formats []string, sceneType string, opts ...clientapi.CallOption) (scene PhotoScene, err error) {
 	// TODO(gdey): sceneType should be a custom type
 	if sceneType != "object" && sceneType != "aerial" {
 		err = errors.New("the scene type is not supported. Expecting 'object' or 'aerial', got " + sceneType)
@@ -71,8 +83,8 @@ func (api API) CreatePhotoScene(name string, formats []string, sceneType string)
 		"scenetype": []string{sceneType},
 	}
 	response, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodPost,
-		scopes.DataWrite,
+		clientapi.CallContext(OpCreatePhotoScene, opts...), OpCreatePhotoScene.Method,
+		OpCreatePhotoScene.Scope,
 		api.Path("photoscene"),
 		nil,
 		nil,
@@ -98,7 +110,7 @@ func (api API) CreatePhotoScene(name string, formats []string, sceneType string)
 
 // AddFileToSceneUsingLink can be used when the needed images are already available remotely
 // and can be uploaded just by providing the remote link
-func (api API) AddFileToSceneUsingLink(sceneID string, link string) (uploads FileUploadingReply, err error) {
+func (api API) AddFileToSceneUsingLink(sceneID string, link string, opts ...clientapi.CallOption) (uploads FileUploadingReply, err error) {
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 	writer.WriteField("photosceneid", sceneID)
@@ -106,8 +118,8 @@ func (api API) AddFileToSceneUsingLink(sceneID string, link string) (uploads Fil
 	writer.WriteField("file[0]", link)
 	writer.Close()
 	response, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodPost,
-		scopes.DataWrite,
+		clientapi.CallContext(OpAddFiles, opts...), OpAddFiles.Method,
+		OpAddFiles.Scope,
 		api.Path("file"),
 		nil,
 		nil,
@@ -132,7 +144,7 @@ func (api API) AddFileToSceneUsingLink(sceneID string, link string) (uploads Fil
 
 // AddFileToSceneUsingData can be used when the image is already available as a byte slice,
 // be it read from a local file or as a result/body of a POST request
-func (api API) AddFileToSceneUsingData(sceneID string, data []byte) (uploads FileUploadingReply, err error) {
+func (api API) AddFileToSceneUsingData(sceneID string, data []byte, opts ...clientapi.CallOption) (uploads FileUploadingReply, err error) {
 	var body bytes.Buffer
 	writer := multipart.NewWriter(&body)
 	writer.WriteField("photosceneid", sceneID)
@@ -145,8 +157,8 @@ func (api API) AddFileToSceneUsingData(sceneID string, data []byte) (uploads Fil
 	writer.Close()
 
 	response, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodPost,
-		scopes.DataWrite,
+		clientapi.CallContext(OpAddFiles, opts...), OpAddFiles.Method,
+		OpAddFiles.Scope,
 		api.Path("file"),
 		nil,
 		nil,
@@ -168,10 +180,10 @@ func (api API) AddFileToSceneUsingData(sceneID string, data []byte) (uploads Fil
 }
 
 // StartSceneProcessing will trigger the processing of a specified scene that can be canceled any time
-func (api API) StartSceneProcessing(sceneID string) (result SceneStartProcessingReply, err error) {
+func (api API) StartSceneProcessing(sceneID string, opts ...clientapi.CallOption) (result SceneStartProcessingReply, err error) {
 	response, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodPost,
-		scopes.DataWrite,
+		clientapi.CallContext(OpStartSceneProcessing, opts...), OpStartSceneProcessing.Method,
+		OpStartSceneProcessing.Scope,
 		api.Path("photoscene", sceneID),
 		nil, nil, clientapi.ContentTypeJSON, nil,
 	)
@@ -191,10 +203,10 @@ func (api API) StartSceneProcessing(sceneID string) (result SceneStartProcessing
 
 // GetSceneProgress polls the scene processing status and progress
 //	Note: instead of polling, consider using the callback parameter that can be specified upon scene creation
-func (api API) GetSceneProgress(sceneID string) (progress SceneProgressReply, err error) {
+func (api API) GetSceneProgress(sceneID string, opts ...clientapi.CallOption) (progress SceneProgressReply, err error) {
 	response, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodGet,
-		scopes.DataRead,
+		clientapi.CallContext(OpGetSceneProgress, opts...), OpGetSceneProgress.Method,
+		OpGetSceneProgress.Scope,
 		api.Path("photoscene", sceneID, "progress"),
 		nil, nil, clientapi.ContentTypeJSON, nil,
 	)
@@ -215,10 +227,10 @@ func (api API) GetSceneProgress(sceneID string) (progress SceneProgressReply, er
 // GetSceneResults requests result in a specified format
 //	Note: The link specified in SceneResultReplies will be available for the time specified in reply,
 //	even if the scene is deleted
-func (api API) GetSceneResults(sceneID string, format string) (result SceneResultReply, err error) {
+func (api API) GetSceneResults(sceneID string, format string, opts ...clientapi.CallOption) (result SceneResultReply, err error) {
 	response, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodGet,
-		scopes.DataRead,
+		clientapi.CallContext(OpGetSceneResults, opts...), OpGetSceneResults.Method,
+		OpGetSceneResults.Scope,
 		api.Path("photoscene", sceneID),
 		[]clientapi.Filterer{filters.QueryParam{Key: "format", Value: format}},
 		nil, clientapi.ContentTypeJSON, nil,
@@ -238,11 +250,11 @@ func (api API) GetSceneResults(sceneID string, format string) (result SceneResul
 }
 
 // CancelSceneProcessing stops the scene processing, without affecting the already uploaded resources
-func (api API) CancelSceneProcessing(sceneID string) (ID string, err error) {
+func (api API) CancelSceneProcessing(sceneID string, opts ...clientapi.CallOption) (ID string, err error) {
 	var result SceneCancelReply
 	response, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodGet,
-		scopes.DataWrite,
+		clientapi.CallContext(OpCancelSceneProcessing, opts...), OpCancelSceneProcessing.Method,
+		OpCancelSceneProcessing.Scope,
 		api.Path("photoscene", sceneID, "cancel"),
 		nil,
 		nil, clientapi.ContentTypeFormEncoded, nil,
@@ -262,12 +274,11 @@ func (api API) CancelSceneProcessing(sceneID string) (ID string, err error) {
 }
 
 // DeleteScene removes all the resources associated with given scene.
-func (api API) DeleteScene(sceneID string) (ID string, err error) {
-	return sceneID, nil
+func (api API) DeleteScene(sceneID string, opts ...clientapi.CallOption) (ID string, err error) {
 	var result SceneDeletionReply
 	response, err := api.Client.DoRawRequest(
-		context.Background(), http.MethodDelete,
-		scopes.DataWrite,
+		clientapi.CallContext(OpDeleteScene, opts...), OpDeleteScene.Method,
+		OpDeleteScene.Scope,
 		api.Path("photoscene", sceneID),
 		nil,
 		nil, clientapi.ContentTypeFormEncoded, nil,
@@ -2,9 +2,9 @@ package recap_test
 
 import (
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
-	"net/http"
 	"os"
 	"strconv"
 	"testing"
@@ -31,9 +31,9 @@ func TestReCapAPIWorkflowUsingRemoteLinks(t *testing.T) {
 	testingFormat := "obj"
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
+	client := env.GetClientTest(t)
 
-	recapAPI := recap.NewAPIWithCredentials(clientID, clientSecret)
+	recapAPI := recap.API{Client: client, ForgeAuthenticator: client.ForgeAuthenticator}
 
 	t.Run("Creating a new photoScene", func(t *testing.T) {
 		var err error
@@ -100,19 +100,25 @@ func TestReCapAPIWorkflowUsingRemoteLinks(t *testing.T) {
 
 		filename := "temp.zip"
 
-		resp, err := http.Get(response.PhotoScene.SceneLink)
+		resp, err := env.HTTPClient(t).Get(response.PhotoScene.SceneLink)
 
 		if err != nil {
-			return
+			t.Fatal(err.Error())
 		}
 		defer resp.Body.Close()
 		result, err := os.Create(filename)
 		if err != nil {
-			return
+			t.Fatal(err.Error())
 		}
 		defer result.Close()
+		if _, err = io.Copy(result, resp.Body); err != nil {
+			t.Fatal(err.Error())
+		}
 
 		tempFile, err := os.Stat(filename)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
 
 		if tempFile.Size() <= 22 {
 			t.Error("The scene was processed, but the result file is abnormally small: ", tempFile.Size())
@@ -155,9 +161,9 @@ func TestReCapAPIWorkflowUsingLocalFiles(t *testing.T) {
 	testingFormat := "obj"
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
+	client := env.GetClientTest(t)
 
-	recapAPI := recap.NewAPIWithCredentials(clientID, clientSecret)
+	recapAPI := recap.API{Client: client, ForgeAuthenticator: client.ForgeAuthenticator}
 
 	t.Run("Creating a new photoScene", func(t *testing.T) {
 		var err error
@@ -172,7 +178,7 @@ func TestReCapAPIWorkflowUsingLocalFiles(t *testing.T) {
 
 		//download each link locally and then upload the data
 		for _, link := range linkSamples {
-			response, err := http.Get(link)
+			response, err := env.HTTPClient(t).Get(link)
 			if err != nil {
 				t.Fatal(err.Error())
 			}
@@ -239,19 +245,25 @@ func TestReCapAPIWorkflowUsingLocalFiles(t *testing.T) {
 
 		filename := "temp.zip"
 
-		resp, err := http.Get(response.PhotoScene.SceneLink)
+		resp, err := env.HTTPClient(t).Get(response.PhotoScene.SceneLink)
 
 		if err != nil {
-			return
+			t.Fatal(err.Error())
 		}
 		defer resp.Body.Close()
 		result, err := os.Create(filename)
 		if err != nil {
-			return
+			t.Fatal(err.Error())
 		}
 		defer result.Close()
+		if _, err = io.Copy(result, resp.Body); err != nil {
+			t.Fatal(err.Error())
+		}
 
 		tempFile, err := os.Stat(filename)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
 
 		if tempFile.Size() <= 22 {
 			t.Error("The scene was processed, but the result file is abnormally small: ", tempFile.Size())
@@ -278,8 +290,8 @@ func TestReCapAPIWorkflowUsingLocalFiles(t *testing.T) {
 func TestCreatePhotoScene(t *testing.T) {
 
 	// prepare the credentials
-	clientID, clientSecret := env.GetClientSecretTest(t)
-	recapAPI := recap.NewAPIWithCredentials(clientID, clientSecret)
+	client := env.GetClientTest(t)
+	recapAPI := recap.API{Client: client, ForgeAuthenticator: client.ForgeAuthenticator}
 	var sceneID string
 
 	t.Run("Create a scene", func(t *testing.T) {
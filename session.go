@@ -0,0 +1,80 @@
+package forge
+
+import (
+	"context"
+
+	"github.com/gdey/forge-api-go-client/api"
+	"github.com/gdey/forge-api-go-client/config"
+	"github.com/gdey/forge-api-go-client/dm"
+	"github.com/gdey/forge-api-go-client/md"
+	"github.com/gdey/forge-api-go-client/oauth"
+	"github.com/gdey/forge-api-go-client/recap"
+)
+
+// Session owns one api client and authenticator shared by all the service APIs,
+// so they share connections, configuration and cached tokens. g.e.
+//
+//	session, err := forge.NewSession(ctx, cfg)
+//	buckets, err := session.OSS().ListBuckets(nil)
+//	hubs, err := session.DataManagement().Hubs.GetHubs(nil)
+type Session struct {
+	Config config.Config
+	Client *api.Client
+}
+
+// NewSession returns a session authenticating with the credentials of cfg, see config.Config.Client
+func NewSession(ctx context.Context, cfg config.Config) (*Session, error) {
+	client, err := cfg.Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &Session{Config: cfg, Client: client}, nil
+}
+
+// NewSessionWithAuth returns a session using auth, g.e. a 3-legged token or a tenant pool,
+// with the paths, timeout and retry policy of cfg
+func NewSessionWithAuth(cfg config.Config, auth oauth.ForgeAuthenticator) *Session {
+	return &Session{Config: cfg, Client: cfg.ClientFor(auth)}
+}
+
+// NewSessionWithCredentials returns a session with default configurations for the client
+// credentials; with an empty clientID the credentials are looked up, see config.Config.AuthData
+func NewSessionWithCredentials(clientID, clientSecret string) (*Session, error) {
+	cfg := config.Config{
+		Credentials: config.Credentials{ClientID: clientID, ClientSecret: oauth.Secret(clientSecret)},
+	}
+	return NewSession(context.Background(), cfg)
+}
+
+// DataManagement groups the Data Management APIs
+type DataManagement struct {
+	Hubs    dm.HubAPI
+	Folders dm.FolderAPI
+}
+
+// OSS returns the Object Storage Service API, for buckets and objects
+func (s *Session) OSS() dm.BucketAPI {
+	return dm.BucketAPI{Client: s.Client, APIPath: s.Config.Paths.Buckets, Region: s.Config.Region}
+}
+
+// DataManagement returns the Data Management APIs, for hubs, projects, folders and items
+func (s *Session) DataManagement() DataManagement {
+	return DataManagement{
+		Hubs:    dm.HubAPI{Client: s.Client, APIPath: s.Config.Paths.Hubs},
+		Folders: dm.FolderAPI{Client: s.Client, APIPath: s.Config.Paths.Folders},
+	}
+}
+
+// ModelDerivative returns the Model Derivative API, for the region of the session
+func (s *Session) ModelDerivative() md.ModelDerivativeAPI {
+	return md.ModelDerivativeAPI{Client: s.Client, APIPath: md.APIPathFor(s.Config)}
+}
+
+// Reality returns the Reality Capture (ReCap) API
+func (s *Session) Reality() recap.API {
+	return recap.API{
+		Client:             s.Client,
+		APIPath:            s.Config.Paths.Recap,
+		ForgeAuthenticator: s.Client.ForgeAuthenticator,
+	}
+}
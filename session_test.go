@@ -0,0 +1,100 @@
+package forge_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+
+	forge "github.com/gdey/forge-api-go-client"
+	"github.com/gdey/forge-api-go-client/config"
+	"github.com/gdey/forge-api-go-client/oauth/credentials"
+)
+
+func TestSession(t *testing.T) {
+	var (
+		mutex           sync.Mutex
+		authentications int
+		paths           []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if r.URL.Path == "/authentication/v1/authenticate" {
+			authentications++
+			w.Write([]byte(`{"token_type":"Bearer","expires_in":3599,"access_token":"app-token"}`))
+			return
+		}
+		paths = append(paths, path.Clean(r.URL.Path))
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	cfg := config.Config{
+		Host:        server.URL,
+		Region:      config.RegionEMEA,
+		Credentials: config.Credentials{ClientID: "id", ClientSecret: "secret"},
+	}
+	session, err := forge.NewSession(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+
+	if _, err = session.OSS().ListBuckets(nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if _, err = session.DataManagement().Hubs.GetHubs(nil); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if _, err = session.DataManagement().Folders.GetFolderDetails("project", "folder"); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if _, err = session.ModelDerivative().GetManifest("urn"); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+	if _, err = session.Reality().GetSceneProgress("scene"); err != nil {
+		t.Fatalf("Unexpected error: %s\n", err.Error())
+	}
+
+	// one token for the bucket scope, shared by the data scope APIs
+	if authentications != 2 {
+		t.Errorf("Expected the APIs to share tokens, got %d authentications", authentications)
+	}
+	expected := []string{
+		"/oss/v2/buckets",
+		"/project/v1/hubs",
+		"/data/v1/projects/project/folders/folder",
+		"/modelderivative/v2/regions/eu/designdata/urn/manifest",
+		"/photo-to-3d/v1/photoscene/scene/progress",
+	}
+	if strings.Join(paths, " ") != strings.Join(expected, " ") {
+		t.Errorf("Expected requests to %v, got %v", expected, paths)
+	}
+}
+
+func TestNewSessionWithCredentials(t *testing.T) {
+	t.Run("credentials", func(t *testing.T) {
+		session, err := forge.NewSessionWithCredentials("id", "secret")
+		if err != nil {
+			t.Fatalf("Unexpected error: %s\n", err.Error())
+		}
+		if session.Client == nil || session.Client.ForgeAuthenticator == nil {
+			t.Errorf("Expected an authenticated client, got %+v", session.Client)
+		}
+	})
+
+	t.Run("no credentials found", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("HOME", dir)
+		t.Setenv(credentials.EnvClientID, "")
+		t.Setenv(credentials.EnvClientSecret, "")
+		t.Setenv(credentials.EnvCredentialsFile, dir+"/missing")
+		t.Setenv(credentials.EnvProfile, "")
+		if session, err := forge.NewSessionWithCredentials("", ""); err == nil {
+			t.Errorf("Expected an error, got %+v", session)
+		}
+	})
+}